@@ -0,0 +1,84 @@
+// Package bookmarks persists user-starred documentation entries.
+package bookmarks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Bookmark is a saved documentation entry.
+type Bookmark struct {
+	Slug  string    `json:"slug"`  // Doc the entry belongs to
+	Path  string    `json:"path"`  // Entry path (may include #anchor)
+	Name  string    `json:"name"`  // Entry name
+	Type  string    `json:"type"`  // Entry type/category
+	Added time.Time `json:"added"` // When the bookmark was created
+}
+
+// List is an ordered collection of bookmarks backed by a JSON file.
+type List struct {
+	path  string
+	Items []Bookmark
+}
+
+// Load reads bookmarks from path. A missing file yields an empty list.
+func Load(path string) (*List, error) {
+	l := &List{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &l.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bookmarks: %w", err)
+	}
+	return l, nil
+}
+
+// Add appends a bookmark. Returns false if the entry is already bookmarked.
+func (l *List) Add(b Bookmark) bool {
+	for _, existing := range l.Items {
+		if existing.Slug == b.Slug && existing.Path == b.Path {
+			return false
+		}
+	}
+	if b.Added.IsZero() {
+		b.Added = time.Now()
+	}
+	l.Items = append(l.Items, b)
+	return true
+}
+
+// Remove deletes the bookmark at index i (0-based) and returns it.
+func (l *List) Remove(i int) (Bookmark, error) {
+	if i < 0 || i >= len(l.Items) {
+		return Bookmark{}, fmt.Errorf("no bookmark #%d", i+1)
+	}
+	removed := l.Items[i]
+	l.Items = append(l.Items[:i], l.Items[i+1:]...)
+	return removed, nil
+}
+
+// Save writes the bookmarks back to their file.
+func (l *List) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create bookmarks directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l.Items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bookmarks: %w", err)
+	}
+	if err := os.WriteFile(l.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package bookmarks
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestListRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+
+	l, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() on missing file error = %v", err)
+	}
+	if len(l.Items) != 0 {
+		t.Fatalf("Expected empty list, got %d items", len(l.Items))
+	}
+
+	if !l.Add(Bookmark{Slug: "react", Path: "reference/react/usestate", Name: "useState", Type: "Hooks"}) {
+		t.Error("Add() = false for new bookmark")
+	}
+	if !l.Add(Bookmark{Slug: "go", Path: "net/http/index#Client", Name: "http.Client", Type: "net/http"}) {
+		t.Error("Add() = false for new bookmark")
+	}
+	if l.Add(Bookmark{Slug: "react", Path: "reference/react/usestate"}) {
+		t.Error("Add() = true for duplicate bookmark")
+	}
+
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Items) != 2 {
+		t.Fatalf("Expected 2 bookmarks, got %d", len(loaded.Items))
+	}
+	if loaded.Items[1].Name != "http.Client" {
+		t.Errorf("Items[1].Name = %q, want http.Client", loaded.Items[1].Name)
+	}
+	if loaded.Items[0].Added.IsZero() {
+		t.Error("Expected Added timestamp to be set")
+	}
+}
+
+func TestListRemove(t *testing.T) {
+	t.Parallel()
+
+	l := &List{Items: []Bookmark{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+
+	removed, err := l.Remove(1)
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if removed.Name != "b" {
+		t.Errorf("Removed %q, want b", removed.Name)
+	}
+	if len(l.Items) != 2 || l.Items[1].Name != "c" {
+		t.Errorf("Unexpected items after remove: %+v", l.Items)
+	}
+
+	if _, err := l.Remove(5); err == nil {
+		t.Error("Expected error for out-of-range index, got nil")
+	}
+}
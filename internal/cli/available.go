@@ -10,12 +10,21 @@ import (
 	"github.com/icampana/dsearch/internal/devdocs"
 )
 
+var availableRefresh bool
+
 var availableCmd = &cobra.Command{
 	Use:   "available [query]",
 	Short: "List available documentation from DevDocs",
-	Long:  `Lists all available documentation from DevDocs that can be installed. Use version syntax for specific versions (e.g., dsearch install react@18)`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runAvailable,
+	Long: `Lists all available documentation from DevDocs that can be installed. Use version syntax for specific versions (e.g., dsearch install react@18)
+
+The catalog is cached and revalidated once it is older than manifest_ttl
+(default 24h); use --refresh to revalidate it now.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAvailable,
+}
+
+func init() {
+	availableCmd.Flags().BoolVar(&availableRefresh, "refresh", false, "revalidate the cached catalog with DevDocs")
 }
 
 func runAvailable(cmd *cobra.Command, args []string) error {
@@ -25,20 +34,11 @@ func runAvailable(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create directories: %w", err)
 	}
 
-	// Try to load cached manifest first
-	store := devdocs.NewStore(cfg.DataDir, cfg.CacheDir)
-	manifest, err := store.LoadManifest()
-
-	// If not cached or stale, fetch from DevDocs
+	// Use the cached manifest, revalidating it when stale or with --refresh
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir))
+	manifest, err := loadManifest(cmd.Context(), newClient(), store, availableRefresh)
 	if err != nil {
-		client := devdocs.NewClient()
-		manifest, err = client.FetchManifest()
-		if err != nil {
-			return fmt.Errorf("fetching available docs: %w", err)
-		}
-		if err := store.SaveManifest(manifest); err != nil {
-			return fmt.Errorf("caching manifest: %w", err)
-		}
+		return fmt.Errorf("fetching available docs: %w", err)
 	}
 
 	if len(manifest) == 0 {
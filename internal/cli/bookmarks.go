@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/bookmarks"
+)
+
+var bookmarksCmd = &cobra.Command{
+	Use:   "bookmarks",
+	Short: "List bookmarked entries",
+	Long: `Lists bookmarked documentation entries.
+
+Examples:
+  dsearch bookmarks add useState -d react   # Bookmark the best match
+  dsearch bookmarks                         # List bookmarks
+  dsearch bookmarks show 1                  # Show bookmark #1
+  dsearch bookmarks remove 1                # Remove bookmark #1`,
+	Args: cobra.NoArgs,
+	RunE: runBookmarksList,
+}
+
+var bookmarksAddCmd = &cobra.Command{
+	Use:   "add <query>",
+	Short: "Bookmark the best match for a query",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBookmarksAdd,
+}
+
+var bookmarksShowCmd = &cobra.Command{
+	Use:   "show <n>",
+	Short: "Show the content of a bookmarked entry",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBookmarksShow,
+}
+
+var bookmarksRemoveCmd = &cobra.Command{
+	Use:     "remove <n>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a bookmark by its number",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runBookmarksRemove,
+}
+
+func init() {
+	addRenderFlags(bookmarksShowCmd)
+	bookmarksCmd.AddCommand(bookmarksAddCmd)
+	bookmarksCmd.AddCommand(bookmarksShowCmd)
+	bookmarksCmd.AddCommand(bookmarksRemoveCmd)
+}
+
+func loadBookmarks() (*bookmarks.List, error) {
+	return bookmarks.Load(filepath.Join(paths.DataDir, "bookmarks.json"))
+}
+
+func runBookmarksList(cmd *cobra.Command, args []string) error {
+	list, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+
+	if len(list.Items) == 0 {
+		fmt.Println("No bookmarks yet.")
+		fmt.Println("\nTo bookmark the best match for a query, run:")
+		fmt.Println("  dsearch bookmarks add <query>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tNAME\tTYPE\tDOC\tPATH")
+	for i, b := range list.Items {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, b.Name, b.Type, b.Slug, b.Path)
+	}
+	return w.Flush()
+}
+
+func runBookmarksAdd(cmd *cobra.Command, args []string) error {
+	engine, _, err := loadSearchEngine(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	results, _, err := engine.Search(args[0], nil)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no results found for %q", args[0])
+	}
+
+	list, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+
+	result := results[0]
+	if !list.Add(bookmarks.Bookmark{Slug: result.Slug, Path: result.Path, Name: result.Name, Type: result.Type}) {
+		fmt.Printf("%s (%s) is already bookmarked\n", result.Name, result.Slug)
+		return nil
+	}
+	if err := list.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Bookmarked %s (%s)\n", result.Name, result.Slug)
+	return nil
+}
+
+func runBookmarksShow(cmd *cobra.Command, args []string) error {
+	list, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+
+	b, err := bookmarkAt(list, args[0])
+	if err != nil {
+		return err
+	}
+
+	return runShow(cmd, []string{b.Slug, b.Path})
+}
+
+func runBookmarksRemove(cmd *cobra.Command, args []string) error {
+	list, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid bookmark number %q", args[0])
+	}
+	removed, err := list.Remove(n - 1)
+	if err != nil {
+		return err
+	}
+	if err := list.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed bookmark %s (%s)\n", removed.Name, removed.Slug)
+	return nil
+}
+
+// bookmarkAt returns the bookmark for a 1-based number given on the command line.
+func bookmarkAt(list *bookmarks.List, arg string) (bookmarks.Bookmark, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(list.Items) {
+		return bookmarks.Bookmark{}, fmt.Errorf("no bookmark #%s (run 'dsearch bookmarks' to list them)", arg)
+	}
+	return list.Items[n-1], nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var (
+	bundleOutput  string
+	bundleDecrypt bool
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle <doc>...",
+	Short: "Build prebuilt bundles from installed documentation",
+	Long: `Writes each installed doc as a prebuilt bundle (<slug>.tar.gz) that can be
+published on a web server. Clients with bundle_url (or $DSEARCH_BUNDLE_URL)
+pointing at that server install from the bundles first, falling back to DevDocs
+when a bundle is missing or older than the DevDocs release.
+
+Bundles are not encrypted. Docs encrypted at rest are refused unless --decrypt
+is given, which writes their content in plaintext.
+
+Examples:
+  dsearch bundle go react -o ./public`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBundle,
+}
+
+func init() {
+	bundleCmd.Flags().StringVarP(&bundleOutput, "output", "o", ".", "directory to write bundles to")
+	bundleCmd.Flags().BoolVar(&bundleDecrypt, "decrypt", false, "bundle encrypted docs in plaintext")
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	cfg := config.DefaultPaths()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+
+	if err := os.MkdirAll(bundleOutput, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var bundleErrors []string
+	for _, input := range args {
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			bundleErrors = append(bundleErrors, err.Error())
+			continue
+		}
+
+		if !store.IsInstalled(slug) {
+			bundleErrors = append(bundleErrors, fmt.Sprintf("doc '%s' is not installed", input))
+			continue
+		}
+		path, err := writeBundleFile(store, slug, bundleOutput, bundleDecrypt)
+		if err != nil {
+			bundleErrors = append(bundleErrors, fmt.Sprintf("failed to bundle %s: %v", input, err))
+			continue
+		}
+		fmt.Printf("Wrote %s\n", path)
+	}
+
+	if len(bundleErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d bundle(s) failed:\n", len(bundleErrors))
+		for _, errMsg := range bundleErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d bundle(s) failed (see above)", len(bundleErrors))
+	}
+	return nil
+}
+
+// writeBundleFile writes the bundle of an installed doc into dir. Encrypted
+// docs are only written, in plaintext, with decrypt.
+func writeBundleFile(store *devdocs.Store, slug, dir string, decrypt bool) (string, error) {
+	bundle, err := store.Bundle(slug, decrypt)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, slug+".tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := devdocs.WriteBundle(f, bundle); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
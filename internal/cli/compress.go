@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var compressCmd = &cobra.Command{
+	Use:   "compress [doc]...",
+	Short: "Compress the content of installed documentation",
+	Long: `Compresses the content pages of installed docs in place with zstd, which
+typically shrinks them to a fraction of their size. Pages are decompressed
+transparently when read, and compressed docs stay compressed when upgraded.
+
+Without arguments, every installed doc is compressed. New installs can be
+compressed right away with "dsearch install --compress" or compress: true in
+the config.
+
+Examples:
+  dsearch compress
+  dsearch compress go cpp`,
+	RunE:              runCompress,
+	ValidArgsFunction: completeInstalledDocs,
+}
+
+func runCompress(cmd *cobra.Command, args []string) error {
+	cfg := config.DefaultPaths()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+
+	slugs := make([]string, 0, len(args))
+	for _, input := range args {
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			return err
+		}
+		slugs = append(slugs, slug)
+	}
+	if len(slugs) == 0 {
+		slugs = store.ListInstalled()
+	}
+	if len(slugs) == 0 {
+		fmt.Println("No documentation installed.")
+		return nil
+	}
+
+	var compressErrors []string
+	for _, slug := range slugs {
+		if !store.IsInstalled(slug) {
+			compressErrors = append(compressErrors, fmt.Sprintf("doc '%s' is not installed", slug))
+			continue
+		}
+		before, after, err := store.Compress(slug)
+		if err != nil {
+			compressErrors = append(compressErrors, fmt.Sprintf("failed to compress %s: %v", slug, err))
+			continue
+		}
+		fmt.Printf("Compressed %s (%s -> %s)\n", slug, formatBytes(before), formatBytes(after))
+	}
+
+	if len(compressErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d doc(s) could not be compressed:\n", len(compressErrors))
+		for _, errMsg := range compressErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d compression(s) failed (see above)", len(compressErrors))
+	}
+	return nil
+}
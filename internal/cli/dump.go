@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+	"github.com/icampana/dsearch/internal/render"
+)
+
+var dumpFormat string
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Dump installed entries or page text",
+	Long: `Dumps installed documentation for use in other tools.
+
+Formats:
+  tsv     One line per entry: name, type, doc, path (default)
+  ndjson  One JSON record per page: {"slug", "path", "title", "plain_text"}
+
+Combine TSV output with fzf and 'dsearch show -' to browse docs, or feed
+NDJSON into external search systems:
+
+  dsearch dump | fzf | dsearch show -
+  dsearch dump -d go | fzf --with-nth=1,2 | dsearch show -
+  dsearch dump -d go --format ndjson > go.ndjson`,
+	Args: cobra.NoArgs,
+	RunE: runDump,
+}
+
+func init() {
+	dumpCmd.Flags().StringVarP(&dumpFormat, "format", "f", "tsv", "output format: tsv, ndjson")
+}
+
+// pageRecord is a single page in NDJSON dump output.
+type pageRecord struct {
+	Slug      string `json:"slug"`
+	Path      string `json:"path"`
+	Title     string `json:"title"`
+	PlainText string `json:"plain_text"`
+}
+
+func runDump(cmd *cobra.Command, args []string) error {
+	if dumpFormat != "tsv" && dumpFormat != "ndjson" {
+		return fmt.Errorf("invalid format %q (want tsv or ndjson)", dumpFormat)
+	}
+
+	store := devdocs.NewStore(devdocs.WithDataDir(paths.DataDir), devdocs.WithCacheDir(paths.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+
+	slugs, err := resolveDocs(cmd.Context(), store)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	for _, slug := range slugs {
+		index, err := store.LoadIndex(slug)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load index for %s: %v\n", slug, err)
+			continue
+		}
+
+		if dumpFormat == "ndjson" {
+			if err := dumpPages(w, store, slug, index); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, entry := range index.Entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tsvField(entry.Name), tsvField(entry.Type), slug, entry.Path)
+		}
+	}
+
+	return nil
+}
+
+// dumpPages writes one NDJSON record with the plain text of every page in a doc.
+func dumpPages(w *bufio.Writer, store *devdocs.Store, slug string, index *devdocs.Index) error {
+	pages, err := store.ListPages(slug)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to list pages for %s: %v\n", slug, err)
+		return nil
+	}
+
+	// Title each page after the first entry pointing at it
+	titles := make(map[string]string, len(index.Entries))
+	for _, entry := range index.Entries {
+		page, _ := devdocs.SplitPath(entry.Path)
+		if _, ok := titles[page]; !ok {
+			titles[page] = entry.Name
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	renderer := render.New(render.FormatText)
+	markdown := store.IsMarkdown(slug)
+
+	for _, page := range pages {
+		content, err := store.LoadContent(slug, page)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s/%s: %v\n", slug, page, err)
+			continue
+		}
+		// Pages pre-rendered to markdown are already text
+		text := content
+		if !markdown {
+			if text, err = renderer.Render([]byte(content)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s/%s: %v\n", slug, page, err)
+				continue
+			}
+		}
+
+		title, ok := titles[page]
+		if !ok {
+			title = page
+		}
+
+		record := pageRecord{Slug: slug, Path: page, Title: title, PlainText: strings.TrimSpace(text)}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("writing record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// tsvField replaces characters that would break a TSV line.
+func tsvField(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(s)
+}
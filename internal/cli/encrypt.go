@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt <doc>...",
+	Short: "Encrypt installed documentation at rest",
+	Long: `Encrypts installed docs in place with a key derived from $DSEARCH_PASSPHRASE
+(AES-256-GCM). Encrypted docs stay encrypted when upgraded or reinstalled, and
+the same passphrase is needed to search and read them.
+
+File names are not encrypted: each page is stored as content/<path>.html, so
+the list of pages (the doc's entry paths) stays visible on disk.
+
+Examples:
+  DSEARCH_PASSPHRASE=... dsearch encrypt internal-api`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEncrypt,
+}
+
+func runEncrypt(cmd *cobra.Command, args []string) error {
+	passphrase := os.Getenv(passphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("no passphrase: set $%s", passphraseEnv)
+	}
+
+	cfg := config.DefaultPaths()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir), devdocs.WithPassphrase(passphrase))
+
+	var encryptErrors []string
+	for _, input := range args {
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			encryptErrors = append(encryptErrors, err.Error())
+			continue
+		}
+
+		if !store.IsInstalled(slug) {
+			encryptErrors = append(encryptErrors, fmt.Sprintf("doc '%s' is not installed", input))
+			continue
+		}
+		if err := store.Encrypt(slug); err != nil {
+			encryptErrors = append(encryptErrors, fmt.Sprintf("failed to encrypt %s: %v", input, err))
+			continue
+		}
+		fmt.Printf("Encrypted %s\n", slug)
+	}
+
+	if len(encryptErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d doc(s) could not be encrypted:\n", len(encryptErrors))
+		for _, errMsg := range encryptErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d encryption(s) failed (see above)", len(encryptErrors))
+	}
+	return nil
+}
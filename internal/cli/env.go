@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var (
+	envOutput     string
+	envAllowNewer bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Reproduce the installed docs with a lockfile",
+	Long: `Records the installed docs and their DevDocs builds in a lockfile, and
+installs exactly those docs elsewhere, so a team or CI image gets identical
+offline documentation.
+
+Examples:
+  dsearch env export > dsearch.lock   # Record the installed docs
+  dsearch env install dsearch.lock    # Install the same docs and builds`,
+}
+
+var envExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a lockfile of the installed docs",
+	Args:  cobra.NoArgs,
+	RunE:  runEnvExport,
+}
+
+var envInstallCmd = &cobra.Command{
+	Use:   "install <lockfile>",
+	Short: "Install the docs recorded in a lockfile",
+	Long: `Installs every doc in the lockfile ('-' reads it from stdin). Docs already
+installed at the locked build are skipped, and docs pinned at another build
+are reported as failures until they are unpinned.
+
+DevDocs only serves the latest build of each doc, so a doc whose build has
+changed upstream fails to install; --allow-newer installs the newer build
+instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvInstall,
+}
+
+func init() {
+	envExportCmd.Flags().StringVarP(&envOutput, "output", "o", "", "write to file instead of stdout")
+	envInstallCmd.Flags().BoolVar(&envAllowNewer, "allow-newer", false, "install newer builds of docs whose locked build is no longer served")
+	envCmd.AddCommand(envExportCmd)
+	envCmd.AddCommand(envInstallCmd)
+}
+
+func runEnvExport(cmd *cobra.Command, args []string) error {
+	cfg := config.DefaultPaths()
+	if err := cfg.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir))
+
+	// Releases are informational, so only the cached catalog is used
+	manifest, _ := store.LoadManifest()
+	lock, err := store.Lock(manifest)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if envOutput != "" {
+		f, err := os.Create(envOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create lockfile: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := devdocs.WriteLock(out, lock); err != nil {
+		return err
+	}
+	if envOutput != "" {
+		fmt.Printf("Locked %d doc(s) in %s\n", len(lock.Docs), envOutput)
+	}
+	return nil
+}
+
+func runEnvInstall(cmd *cobra.Command, args []string) error {
+	var in io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open lockfile: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	lock, err := devdocs.ReadLock(in)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.DefaultPaths()
+	if err := cfg.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir),
+		devdocs.WithPassphrase(os.Getenv(passphraseEnv)), devdocs.WithCompression(compressDocs), devdocs.WithPostProcess(postProcess, keepOriginals))
+
+	// Locked builds are compared with the latest catalog
+	manifest, err := loadManifest(cmd.Context(), newClient(), store, true)
+	if err != nil {
+		return err
+	}
+	docsBySlug := make(map[string]devdocs.Doc, len(manifest))
+	for _, doc := range manifest {
+		docsBySlug[doc.Slug] = doc
+	}
+
+	var lockErrors, slugs []string
+	locked := make(map[string]bool, len(lock.Docs))
+	for _, want := range lock.Docs {
+		locked[want.Slug] = true
+		upToDate, err := store.Locked(want)
+		if errors.Is(err, devdocs.ErrPinnedBuild) {
+			lockErrors = append(lockErrors, fmt.Sprintf("%s: pinned at a different build than the lockfile wants (%s); run 'dsearch unpin %s' first",
+				want.Slug, formatMtime(want.Mtime), want.Slug))
+			continue
+		}
+		if upToDate {
+			fmt.Printf("%s is up to date\n", want.Slug)
+			continue
+		}
+
+		doc, ok := docsBySlug[want.Slug]
+		switch {
+		case !ok:
+			lockErrors = append(lockErrors, fmt.Sprintf("doc '%s' not found in DevDocs catalog", want.Slug))
+		case doc.Mtime == want.Mtime:
+			slugs = append(slugs, want.Slug)
+		case doc.Mtime > want.Mtime && envAllowNewer:
+			fmt.Printf("%s: installing the newer build from %s (locked: %s)\n", want.Slug, formatMtime(doc.Mtime), formatMtime(want.Mtime))
+			slugs = append(slugs, want.Slug)
+		default:
+			lockErrors = append(lockErrors, fmt.Sprintf("%s: DevDocs serves the build from %s, but the lockfile wants %s (use --allow-newer to install a newer build)",
+				want.Slug, formatMtime(doc.Mtime), formatMtime(want.Mtime)))
+		}
+	}
+
+	var installErr error
+	if len(slugs) > 0 {
+		installErr = installDocs(cmd.Context(), store, slugs, os.Stdout)
+	}
+
+	var extra []string
+	for _, slug := range store.ListInstalled() {
+		if !locked[slug] {
+			extra = append(extra, slug)
+		}
+	}
+	if len(extra) > 0 {
+		fmt.Printf("\nNote: %d installed doc(s) are not in the lockfile: %s\n", len(extra), strings.Join(extra, ", "))
+	}
+
+	// Report results
+	if len(lockErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d doc(s) could not be installed at the locked build:\n", len(lockErrors))
+		for _, errMsg := range lockErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d doc(s) could not be installed at the locked build (see above)", len(lockErrors))
+	}
+	return installErr
+}
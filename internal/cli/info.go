@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+	"github.com/icampana/dsearch/internal/render"
+)
+
+var infoLicense bool
+
+var infoCmd = &cobra.Command{
+	Use:   "info <doc>",
+	Short: "Show details and the license of a doc",
+	Long: `Shows the release, install details and the copyright and license notice of a
+doc, installed or listed in the DevDocs catalog.
+
+DevDocs content is redistributed under each project's own license. Check the
+notice before sharing installed docs, bundles or rendered pages, or add it to
+rendered output with --attribution (or attribution: true in the config).
+
+Examples:
+  dsearch info react
+  dsearch info --license go`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runInfo,
+	ValidArgsFunction: completeInstalledDocs,
+}
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoLicense, "license", false, "only print the copyright and license notice")
+}
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	store := devdocs.NewStore(devdocs.WithDataDir(paths.DataDir), devdocs.WithCacheDir(paths.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+
+	slug, err := resolveInstalledSlug(store, args[0])
+	if err != nil {
+		return err
+	}
+	installed := store.IsInstalled(slug)
+
+	// The catalog has names and releases, and describes docs that aren't installed
+	manifest, _ := store.LoadManifest()
+	if !installed {
+		manifest, err = loadManifest(cmd.Context(), newClient(), store, false)
+		if err != nil {
+			return err
+		}
+		if slug, err = devdocs.ResolveSlug(manifest, parseDocSlug(args[0])); err != nil {
+			return err
+		}
+	}
+	var doc *devdocs.Doc
+	for i := range manifest {
+		if manifest[i].Slug == slug {
+			doc = &manifest[i]
+			break
+		}
+	}
+	if doc == nil && !installed {
+		return fmt.Errorf("doc '%s' not found in DevDocs catalog", args[0])
+	}
+
+	notice := ""
+	if installed {
+		notice = attributionNotice(store, slug)
+	} else if doc.Attribution != "" {
+		notice = renderNotice(doc.Attribution)
+	}
+
+	if infoLicense {
+		if notice == "" {
+			fmt.Fprintf(os.Stderr, "No license notice recorded for %s.\n", slug)
+			return nil
+		}
+		fmt.Println(notice)
+		return nil
+	}
+
+	name, release := slug, "unknown"
+	if doc != nil {
+		name, release = doc.Name, doc.Release
+		if doc.Version != "" {
+			release = fmt.Sprintf("%s (%s)", doc.Release, doc.Version)
+		}
+	}
+	fmt.Printf("%s %s\n", name, release)
+	fmt.Printf("  Slug:      %s\n", slug)
+	if doc != nil && doc.Alias != "" {
+		fmt.Printf("  Alias:     %s\n", doc.Alias)
+	}
+
+	if installed {
+		meta, err := store.LoadMeta(slug)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  Installed: %s\n", meta.Installed.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("  Source:    %s\n", meta.Source)
+		fmt.Printf("  Location:  %s\n", store.DocDir(slug))
+		if index, err := store.LoadIndex(slug); err == nil {
+			fmt.Printf("  Entries:   %d\n", len(index.Entries))
+		}
+		fmt.Printf("  Size:      %s\n", formatBytes(meta.DBSize))
+		if doc != nil && doc.Mtime > meta.Mtime {
+			fmt.Printf("  Update:    %s available (run 'dsearch upgrade %s')\n", doc.Release, slug)
+		}
+	} else {
+		fmt.Println("  Installed: no")
+		fmt.Printf("  Size:      %s\n", formatBytes(doc.DBSize))
+	}
+
+	if notice != "" {
+		fmt.Println("  License:")
+		for _, line := range strings.Split(notice, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	return nil
+}
+
+// attributionNotice returns the copyright and license notice of an installed
+// doc as text, or "" when none is recorded.
+func attributionNotice(store *devdocs.Store, slug string) string {
+	notice := store.Attribution(slug)
+	if notice == "" {
+		return ""
+	}
+	return renderNotice(notice)
+}
+
+// renderNotice converts an attribution notice from HTML. It is rendered as
+// markdown in every format, since plain text would drop the license links.
+func renderNotice(notice string) string {
+	rendered, err := render.New(render.FormatMD).Render([]byte(notice))
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,9 +1,15 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
@@ -11,12 +17,65 @@ import (
 	"github.com/icampana/dsearch/internal/devdocs"
 )
 
+var (
+	installFile     string
+	installEncrypt  bool
+	installCompress bool
+	installForce    bool
+	installJobs     int
+	installOnly     []string
+	installMatch    []string
+	installYes      bool
+)
+
 var installCmd = &cobra.Command{
 	Use:   "install <doc>...",
 	Short: "Install documentation from DevDocs",
-	Long:  `Downloads and installs documentation from DevDocs. Supports version syntax: react@18 for React 18.`,
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runInstall,
+	Long: `Downloads and installs documentation from DevDocs. Supports version syntax: react@18 for React 18.
+
+Docs can also be listed in a file (one per line, or a YAML list) to provision
+the same documentation on every machine:
+
+  dsearch install -f docs.txt
+
+Glob patterns install a whole family of docs. The matching docs are listed
+and installed after confirmation (or right away with --yes):
+
+  dsearch install 'python@*'
+  dsearch install --match 'rust*' --yes
+
+Several docs are installed in parallel (--jobs at a time) with a progress
+line per doc; failures are reported together at the end.
+
+Large docs can be installed partially: --only keeps the entries whose type
+or path starts with one of the given prefixes, and only their pages are
+stored. Upgrades keep the same filter:
+
+  dsearch install mdn --only css,javascript
+
+Proprietary docs can be encrypted at rest with a passphrase from
+$DSEARCH_PASSPHRASE, which is then needed to search and read them:
+
+  DSEARCH_PASSPHRASE=... dsearch install --encrypt go`,
+	RunE: runInstall,
+}
+
+func init() {
+	installCmd.Flags().StringVarP(&installFile, "file", "f", "", "install docs listed in a file (.txt or .yaml)")
+	installCmd.Flags().StringSliceVar(&installMatch, "match", nil, "install every doc whose slug matches a glob pattern, e.g. 'python~*'")
+	installCmd.Flags().BoolVarP(&installYes, "yes", "y", false, "install docs matching patterns without asking")
+	installCmd.Flags().IntVarP(&installJobs, "jobs", "j", 4, "number of docs to install in parallel")
+	installCmd.Flags().StringSliceVar(&installOnly, "only", nil, "only install entries whose type or path starts with these prefixes, e.g. css,javascript")
+	installCmd.Flags().BoolVar(&installForce, "force", false, "reinstall docs even if they are pinned")
+	installCmd.Flags().BoolVar(&installCompress, "compress", false, "store content zstd-compressed (default from the compress config key)")
+	installCmd.Flags().BoolVar(&installEncrypt, "encrypt", false, "encrypt docs at rest with the passphrase from $"+passphraseEnv)
+}
+
+// resolveInstalledSlug converts user input like "react@18" or an alias like
+// "ng" to the slug of an installed doc. Inputs that match nothing are returned
+// as slugs, so callers report them as not installed.
+func resolveInstalledSlug(store *devdocs.Store, input string) (string, error) {
+	return store.ResolveInstalled(parseDocSlug(input))
 }
 
 // parseDocSlug converts user input like "react@18" to DevDocs slug "react~18"
@@ -31,38 +90,133 @@ func parseDocSlug(input string) string {
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
+	if installFile != "" {
+		fileDocs, err := config.LoadDocList(installFile)
+		if err != nil {
+			return err
+		}
+		args = append(args, fileDocs...)
+	}
+	if len(args) == 0 && len(installMatch) == 0 {
+		return fmt.Errorf("no docs to install: pass doc names, --match or --file")
+	}
+
 	// Initialize paths
 	cfg := config.DefaultPaths()
 	if err := cfg.EnsureDirs(); err != nil {
 		return fmt.Errorf("failed to create directories: %w", err)
 	}
 
-	// Create DevDocs client and store
-	// Empty string uses default DevDocs URLs (devdocs.io for manifest, documents.devdocs.io for content)
-	client := devdocs.NewClient()
-	store := devdocs.NewStore(cfg.DataDir, cfg.CacheDir)
+	// Create DevDocs store
+	storeOpts := []devdocs.StoreOption{devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir)}
+	if installEncrypt {
+		if os.Getenv(passphraseEnv) == "" {
+			return fmt.Errorf("--encrypt needs a passphrase in $%s", passphraseEnv)
+		}
+		storeOpts = append(storeOpts, devdocs.WithEncryption(os.Getenv(passphraseEnv)))
+	} else {
+		storeOpts = append(storeOpts, devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+	}
+	storeOpts = append(storeOpts, devdocs.WithCompression(installCompress || compressDocs), devdocs.WithOnly(installOnly),
+		devdocs.WithPostProcess(postProcess, keepOriginals))
+	store := devdocs.NewStore(storeOpts...)
+
+	args, err := expandPatterns(cmd.Context(), store, append(args, installMatch...))
+	if err != nil || len(args) == 0 {
+		return err
+	}
+
+	return installDocs(cmd.Context(), store, args, os.Stdout)
+}
+
+// expandPatterns replaces glob patterns among inputs (e.g. "python@*") with
+// the catalog docs they match. The matches are listed and only installed
+// after confirmation or with --yes; otherwise no inputs are returned.
+func expandPatterns(ctx context.Context, store *devdocs.Store, inputs []string) ([]string, error) {
+	var expanded, patterns []string
+	seen := make(map[string]bool)
+	for _, input := range inputs {
+		if devdocs.IsDocPattern(input) {
+			patterns = append(patterns, parseDocSlug(input))
+			continue
+		}
+		expanded = append(expanded, input)
+		seen[parseDocSlug(input)] = true
+	}
+	if len(patterns) == 0 {
+		return inputs, nil
+	}
 
-	// Fetch manifest (or use cached)
-	manifest, err := store.LoadManifest()
+	manifest, err := loadManifest(ctx, newClient(), store, false)
 	if err != nil {
-		// Manifest not cached, fetch it
-		manifest, err = client.FetchManifest()
+		return nil, err
+	}
+
+	var matches []devdocs.Doc
+	for _, pattern := range patterns {
+		docs, err := devdocs.MatchDocs(manifest, pattern)
 		if err != nil {
-			return fmt.Errorf("failed to fetch manifest: %w", err)
+			return nil, err
+		}
+		if len(docs) == 0 {
+			return nil, fmt.Errorf("no docs in the DevDocs catalog match '%s'", pattern)
 		}
-		if err := store.SaveManifest(manifest); err != nil {
-			return fmt.Errorf("failed to cache manifest: %w", err)
+		for _, doc := range docs {
+			if !seen[doc.Slug] {
+				seen[doc.Slug] = true
+				matches = append(matches, doc)
+			}
 		}
 	}
+	if len(matches) == 0 {
+		return expanded, nil
+	}
+
+	fmt.Printf("Docs matching %s:\n\n", strings.Join(patterns, ", "))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSLUG\tRELEASE\tSIZE")
+	var total int64
+	for _, doc := range matches {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", doc.Name, doc.Slug, doc.Release, formatBytes(doc.DBSize))
+		total += doc.DBSize
+	}
+	w.Flush()
+	fmt.Printf("\n%d doc(s), %s to download\n\n", len(matches), formatBytes(total))
+
+	if !installYes && !confirm(fmt.Sprintf("Install %d doc(s)?", len(matches))) {
+		fmt.Println("Nothing installed. Run with --yes to install them.")
+		return nil, nil
+	}
+
+	for _, doc := range matches {
+		expanded = append(expanded, doc.Slug)
+	}
+	return expanded, nil
+}
+
+// installDocs installs docs given as user input (e.g. "react@18") with up to
+// installJobs docs in parallel, writing progress to out. Failures are
+// collected and reported together.
+func installDocs(ctx context.Context, store *devdocs.Store, inputs []string, out io.Writer) error {
+	client := newClient()
+
+	// Use the cached manifest, revalidating it when stale
+	manifest, err := loadManifest(ctx, client, store, false)
+	if err != nil {
+		return err
+	}
 
-	// Install each doc
 	var installErrors []string
-	successCount := 0
+	var docsToInstall []*devdocs.Doc
 
-	for _, input := range args {
-		slug := parseDocSlug(input)
+	// Find docs in manifest
+	for _, input := range inputs {
+		slug, err := devdocs.ResolveSlug(manifest, parseDocSlug(input))
+		if err != nil {
+			installErrors = append(installErrors, err.Error())
+			continue
+		}
 
-		// Find doc in manifest
 		var doc *devdocs.Doc
 		for i := range manifest {
 			if manifest[i].Slug == slug {
@@ -71,41 +225,54 @@ func runInstall(cmd *cobra.Command, args []string) error {
 			}
 		}
 		if doc == nil {
-			installErrors = append(installErrors, fmt.Sprintf("doc '%s' not found in DevDocs catalog", input))
-			continue
+			var notFound string
+			if doc, notFound = suggestDoc(manifest, input); doc == nil {
+				installErrors = append(installErrors, notFound)
+				continue
+			}
 		}
+		docsToInstall = append(docsToInstall, doc)
+	}
 
-		fmt.Printf("Installing %s (%s, %s)...\n", doc.Name, doc.Release, formatBytes(doc.DBSize))
+	// Install docs with a bounded worker pool
+	progress := newMultiProgress(out)
+	bars := make([]*progressBar, len(docsToInstall))
+	for i, doc := range docsToInstall {
+		bars[i] = progress.Add(fmt.Sprintf("%s %s", doc.Name, doc.Release))
+	}
 
-		// Check for updates if already installed
-		if store.IsInstalled(slug) {
-			// TODO: Check mtime for updates
-			fmt.Printf("Already installed, checking for updates...\n")
-		}
+	var mu sync.Mutex
+	var installed []string
+	successCount := 0
+	sem := make(chan struct{}, max(installJobs, 1))
+	var wg sync.WaitGroup
 
-		// Fetch index
-		index, err := client.FetchIndex(slug)
-		if err != nil {
-			installErrors = append(installErrors, fmt.Sprintf("failed to fetch index for %s: %v", input, err))
-			continue
-		}
+	for i, doc := range docsToInstall {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		// Fetch db
-		db, err := client.FetchDB(slug)
-		if err != nil {
-			installErrors = append(installErrors, fmt.Sprintf("failed to fetch db for %s: %v", input, err))
-			continue
-		}
+			if err := installDoc(ctx, client, store, doc, manifest, bars[i]); err != nil {
+				bars[i].Done("failed")
+				mu.Lock()
+				installErrors = append(installErrors, err.Error())
+				mu.Unlock()
+				return
+			}
+			mu.Lock()
+			successCount++
+			installed = append(installed, doc.Slug)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
 
-		// Install
-		_, err = store.Install(slug, index, db, manifest)
-		if err != nil {
-			installErrors = append(installErrors, fmt.Sprintf("failed to install %s: %v", input, err))
-			continue
+	if len(installed) > 0 {
+		if err := enforceQuota(store, installed, out); err != nil {
+			fmt.Fprintf(os.Stderr, "%s could not check the disk quota: %v\n", warningPrefix(), err)
 		}
-
-		fmt.Printf("Successfully installed %s (%d entries)\n", doc.Name, len(index.Entries))
-		successCount++
 	}
 
 	// Report results
@@ -123,6 +290,131 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// suggestDoc handles an input missing from the catalog by suggesting similar
+// docs. On a terminal, one of them can be picked instead; otherwise it returns
+// nil and an error message listing the suggestions.
+func suggestDoc(manifest []devdocs.Doc, input string) (*devdocs.Doc, string) {
+	notFound := fmt.Sprintf("doc '%s' not found in DevDocs catalog", input)
+	suggestions := devdocs.SuggestDocs(manifest, parseDocSlug(input), 5)
+	if len(suggestions) == 0 {
+		return nil, notFound
+	}
+
+	options := make([]string, len(suggestions))
+	for i, doc := range suggestions {
+		options[i] = fmt.Sprintf("%s (%s %s)", doc.Slug, doc.Name, doc.Release)
+	}
+	if i := choose(notFound+". Did you mean:", options); i >= 0 {
+		return &suggestions[i], ""
+	}
+
+	slugs := make([]string, len(suggestions))
+	for i, doc := range suggestions {
+		slugs[i] = doc.Slug
+	}
+	return nil, fmt.Sprintf("%s (did you mean: %s?)", notFound, strings.Join(slugs, ", "))
+}
+
+// installDoc installs a single doc, reporting progress on bar.
+func installDoc(ctx context.Context, client *devdocs.Client, store *devdocs.Store, doc *devdocs.Doc, manifest []devdocs.Doc, bar *progressBar) error {
+	if store.IsPinned(doc.Slug) && !installForce {
+		bar.Done("pinned, skipped (use --force to reinstall)")
+		return nil
+	}
+	if store.IsInstalled(doc.Slug) {
+		bar.SetStatus("already installed, updating")
+	}
+
+	// Fetch index (and a prebuilt bundle when available)
+	index, source, opts, err := fetchDoc(ctx, client, doc, bar)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %v", doc.Slug, err)
+	}
+
+	// Install, streaming content to disk
+	meta, err := store.InstallFrom(doc.Slug, index, source, manifest, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to install %s: %v", doc.Slug, err)
+	}
+	if len(meta.Only) > 0 {
+		index = devdocs.FilterIndex(index, meta.Only)
+	}
+
+	bar.Done(fmt.Sprintf("installed (%d entries, %s)", len(index.Entries), formatBytes(doc.DBSize)))
+	return nil
+}
+
+// newClient creates a DevDocs client honoring the configured mirrors, timeout,
+// User-Agent and bundle URL. Interrupted db.json downloads are kept in the
+// cache directory and resumed. Without mirrors, devdocs.io serves the manifest
+// and documents.devdocs.io the content.
+func newClient(opts ...devdocs.ClientOption) *devdocs.Client {
+	ua := userAgent
+	if ua == "" {
+		ua = "dsearch/" + Version
+	}
+	clientOpts := []devdocs.ClientOption{
+		devdocs.WithUserAgent(ua),
+		devdocs.WithBundleURL(bundleURL),
+		devdocs.WithDownloadDir(filepath.Join(paths.CacheDir, "downloads")),
+	}
+	if manifestURL != "" {
+		clientOpts = append(clientOpts, devdocs.WithManifestURL(manifestURL))
+	}
+	if contentURL != "" {
+		clientOpts = append(clientOpts, devdocs.WithContentURL(contentURL))
+	}
+	if httpTimeout > 0 {
+		clientOpts = append(clientOpts, devdocs.WithTimeout(httpTimeout))
+	}
+	return devdocs.NewClient(append(clientOpts, opts...)...)
+}
+
+// fetchDoc fetches a doc's index and returns a source for its content, with
+// the options to install it with. A prebuilt bundle is used when one is
+// published and up to date; otherwise the content is streamed from DevDocs
+// while it is installed. Every phase reports its progress on bar.
+func fetchDoc(ctx context.Context, client *devdocs.Client, doc *devdocs.Doc, bar *progressBar) (*devdocs.Index, devdocs.ContentSource, []devdocs.InstallOption, error) {
+	if client.HasBundles() {
+		bar.SetStatus("fetching prebuilt bundle")
+		bundle, err := client.FetchBundle(ctx, doc.Slug, bar.SetProgress)
+		switch {
+		case err == nil && bundle.Mtime >= doc.Mtime:
+			return bundle.Index, pageProgress(devdocs.MapSource(bundle.DB), len(bundle.DB), bar), []devdocs.InstallOption{devdocs.WithBundle(bundle)}, nil
+		case err == nil:
+			bar.SetStatus("prebuilt bundle is outdated, falling back to DevDocs")
+		case errors.Is(err, devdocs.ErrBundleNotFound):
+			// No bundle for this doc, fall back silently
+		default:
+			bar.SetStatus(fmt.Sprintf("prebuilt bundle unavailable (%v), falling back to DevDocs", err))
+		}
+	}
+
+	bar.SetStatus("fetching index")
+	index, err := client.FetchIndex(ctx, doc.Slug, func(_ devdocs.Phase, done, total int64) {
+		bar.SetProgress("fetching index", done, total)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	source := func(fn devdocs.ContentFunc) error {
+		return client.StreamDB(ctx, doc.Slug, doc.DBSize, fn, bar.SetProgress)
+	}
+	return index, source, nil, nil
+}
+
+// pageProgress reports the pages written from source on bar.
+func pageProgress(source devdocs.ContentSource, pages int, bar *progressBar) devdocs.ContentSource {
+	return func(fn devdocs.ContentFunc) error {
+		var done int64
+		return source(func(path, content string) error {
+			done++
+			bar.SetPages("extracting", done, int64(pages))
+			return fn(path, content)
+		})
+	}
+}
+
 func formatBytes(b int64) string {
 	const unit = 1024
 	if b < unit {
@@ -1,10 +1,9 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
@@ -13,16 +12,24 @@ import (
 	"github.com/icampana/dsearch/internal/devdocs"
 )
 
+var listDetail bool
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List installed documentation",
-	Long:  `Lists all DevDocs documentation installed in the docs directory.`,
-	RunE:  runList,
+	Long: `Lists all DevDocs documentation installed in the docs directory.
+
+Use --detail to show per-type entry counts, install source and on-disk location.`,
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().BoolVar(&listDetail, "detail", false, "show type breakdown, source and location for each doc")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
 	cfg := config.DefaultPaths()
-	store := devdocs.NewStore(cfg.DataDir, cfg.CacheDir)
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
 
 	installedSlugs := store.ListInstalled()
 
@@ -42,8 +49,13 @@ func runList(cmd *cobra.Command, args []string) error {
 		name       string
 		release    string
 		version    string
+		source     string
+		dir        string
 		entryCount int
 		dbSize     int64
+		pinned     bool
+		only       []string
+		types      []devdocs.Type
 	}
 
 	var installed []installedDoc
@@ -57,17 +69,11 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	for _, slug := range installedSlugs {
 		// Load meta.json
-		metaPath := filepath.Join(cfg.DataDir, "docs", slug, "meta.json")
-		data, err := os.ReadFile(metaPath)
+		meta, err := store.LoadMeta(slug)
 		if err != nil {
 			continue
 		}
 
-		var meta devdocs.Meta
-		if err := json.Unmarshal(data, &meta); err != nil {
-			continue
-		}
-
 		// Load index for entry count
 		index, err := store.LoadIndex(slug)
 		if err != nil {
@@ -89,11 +95,46 @@ func runList(cmd *cobra.Command, args []string) error {
 			name:       name,
 			release:    release,
 			version:    version,
+			source:     meta.Source,
+			dir:        store.DocDir(slug),
 			entryCount: len(index.Entries),
 			dbSize:     meta.DBSize,
+			pinned:     meta.Pinned,
+			only:       meta.Only,
+			types:      index.TypeCounts(),
 		})
 	}
 
+	if listDetail {
+		for _, doc := range installed {
+			versionStr := doc.release
+			if doc.version != "" {
+				versionStr = fmt.Sprintf("%s (%s)", doc.release, doc.version)
+			}
+			fmt.Printf("%s %s\n", doc.name, versionStr)
+			fmt.Printf("  Slug:     %s\n", doc.slug)
+			fmt.Printf("  Source:   %s\n", doc.source)
+			fmt.Printf("  Location: %s\n", doc.dir)
+			fmt.Printf("  Entries:  %d\n", doc.entryCount)
+			fmt.Printf("  Size:     %s\n", formatBytes(doc.dbSize))
+			if doc.pinned {
+				fmt.Println("  Pinned:   yes")
+			}
+			if len(doc.only) > 0 {
+				fmt.Printf("  Partial:  %s\n", strings.Join(doc.only, ", "))
+			}
+			if len(doc.types) > 0 {
+				fmt.Println("  Types:")
+				for _, t := range doc.types {
+					fmt.Printf("    %s: %d\n", t.Name, t.Count)
+				}
+			}
+			fmt.Println()
+		}
+		fmt.Printf("%d documentation set(s) installed in %s\n", len(installed), cfg.DataDir)
+		return nil
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(w, "NAME\tVERSION\tENTRIES\tSIZE")
 	fmt.Fprintln(w, "----\t-------\t-------\t----")
@@ -103,6 +144,12 @@ func runList(cmd *cobra.Command, args []string) error {
 		if doc.version != "" {
 			versionStr = fmt.Sprintf("%s (%s)", doc.release, doc.version)
 		}
+		if doc.pinned {
+			versionStr += " [pinned]"
+		}
+		if len(doc.only) > 0 {
+			versionStr += " [partial]"
+		}
 		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n",
 			doc.name,
 			versionStr,
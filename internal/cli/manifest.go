@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+// defaultManifestTTL is how long the cached catalog is used before it is
+// revalidated, unless manifest_ttl says otherwise.
+const defaultManifestTTL = 24 * time.Hour
+
+// manifestTTL is the configured catalog TTL (0 uses defaultManifestTTL)
+var manifestTTL time.Duration
+
+// catalogTTL returns how long the cached catalog is used before revalidating.
+func catalogTTL() time.Duration {
+	if manifestTTL > 0 {
+		return manifestTTL
+	}
+	return defaultManifestTTL
+}
+
+// loadManifest returns the DevDocs catalog. The cached copy is used while it
+// is younger than the TTL; after that (or with refresh) it is revalidated with
+// a conditional request, so an unchanged catalog isn't downloaded again. When
+// revalidation fails without refresh, the cached copy is used with a warning.
+func loadManifest(ctx context.Context, client *devdocs.Client, store *devdocs.Store, refresh bool) ([]devdocs.Doc, error) {
+	cached, err := store.LoadManifest()
+	if err == nil && !refresh {
+		if age, err := store.ManifestAge(); err == nil && age < catalogTTL() {
+			return cached, nil
+		}
+	}
+
+	manifest, err := refreshManifest(ctx, client, store)
+	if err != nil {
+		if cached != nil && !refresh && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Warning: using the cached DevDocs catalog (%v)\n", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// refreshManifest revalidates the cached catalog, downloading and caching it
+// only when it changed.
+func refreshManifest(ctx context.Context, client *devdocs.Client, store *devdocs.Store) ([]devdocs.Doc, error) {
+	cached, err := store.LoadManifest()
+	var validators devdocs.CacheValidators
+	if err == nil {
+		validators = store.LoadManifestValidators()
+	}
+
+	manifest, validators, err := client.FetchManifestIfChanged(ctx, validators)
+	if errors.Is(err, devdocs.ErrNotModified) {
+		if err := store.TouchManifest(); err != nil {
+			return nil, fmt.Errorf("failed to cache manifest: %w", err)
+		}
+		return cached, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.SaveManifest(manifest); err != nil {
+		return nil, fmt.Errorf("failed to cache manifest: %w", err)
+	}
+	if err := store.SaveManifestValidators(validators); err != nil {
+		return nil, fmt.Errorf("failed to cache manifest: %w", err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var (
+	mirrorDecrypt bool
+	mirrorEncrypt bool
+	mirrorForce   bool
+)
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror",
+	Short: "Copy installed documentation to air-gapped machines",
+	Long: `Exports installed docs to a directory of portable bundles, and imports them
+on another machine without contacting DevDocs.
+
+The directory holds one bundle per doc (<slug>.tar.gz, as written by
+"dsearch bundle") and a docs.json catalog of them, so it can also be served
+over HTTP as both manifest_url and bundle_url.
+
+Bundles are not encrypted, so docs encrypted at rest are skipped on export
+unless --decrypt is given. Imports can encrypt the docs again with --encrypt.
+
+Examples:
+  dsearch mirror export /media/usb/dsearch          # Export all installed docs
+  dsearch mirror export /media/usb/dsearch go react
+  dsearch mirror import /media/usb/dsearch          # On the offline machine`,
+}
+
+var mirrorExportCmd = &cobra.Command{
+	Use:   "export <dir> [doc]...",
+	Short: "Export installed docs to a mirror directory",
+	Long: `Exports installed docs (or the given docs) to a mirror directory. Encrypted
+docs are skipped, since bundles are plaintext; --decrypt exports them anyway.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMirrorExport,
+}
+
+var mirrorImportCmd = &cobra.Command{
+	Use:   "import <dir> [doc]...",
+	Short: "Install docs from a mirror directory",
+	Long: `Installs the docs of a mirror directory (or the given docs). Docs already
+installed at the same or a newer build are skipped, and so are pinned docs
+unless --force is given.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMirrorImport,
+}
+
+func init() {
+	mirrorCmd.AddCommand(mirrorExportCmd)
+	mirrorCmd.AddCommand(mirrorImportCmd)
+
+	mirrorExportCmd.Flags().BoolVar(&mirrorDecrypt, "decrypt", false, "export encrypted docs in plaintext")
+	mirrorImportCmd.Flags().BoolVar(&mirrorForce, "force", false, "import newer builds of pinned docs too")
+	mirrorImportCmd.Flags().BoolVar(&mirrorEncrypt, "encrypt", false, "encrypt imported docs at rest with the passphrase from $"+passphraseEnv)
+}
+
+func runMirrorExport(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	cfg := config.DefaultPaths()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+
+	var exportErrors []string
+	var slugs []string
+	for _, input := range args[1:] {
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			exportErrors = append(exportErrors, err.Error())
+			continue
+		}
+		if !store.IsInstalled(slug) {
+			exportErrors = append(exportErrors, fmt.Sprintf("doc '%s' is not installed", input))
+			continue
+		}
+		if store.IsEncrypted(slug) && !mirrorDecrypt {
+			exportErrors = append(exportErrors, fmt.Sprintf("doc '%s' is encrypted (use --decrypt to export it in plaintext)", input))
+			continue
+		}
+		slugs = append(slugs, slug)
+	}
+	if len(args) == 1 {
+		installed := store.ListInstalled()
+		if len(installed) == 0 {
+			fmt.Println("No documentation installed.")
+			return nil
+		}
+		var encrypted []string
+		for _, slug := range installed {
+			if store.IsEncrypted(slug) && !mirrorDecrypt {
+				encrypted = append(encrypted, slug)
+				continue
+			}
+			slugs = append(slugs, slug)
+		}
+		if len(encrypted) > 0 {
+			fmt.Fprintf(os.Stderr, "Skipping %d encrypted doc(s): %s (use --decrypt to export them in plaintext)\n", len(encrypted), strings.Join(encrypted, ", "))
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+
+	var exported []string
+	for _, slug := range slugs {
+		path, err := writeBundleFile(store, slug, dir, mirrorDecrypt)
+		if err != nil {
+			exportErrors = append(exportErrors, fmt.Sprintf("failed to export %s: %v", slug, err))
+			continue
+		}
+		fmt.Printf("Wrote %s\n", path)
+		exported = append(exported, slug)
+	}
+
+	// Keep the docs exported earlier in the catalog, so a mirror can be filled in several runs
+	docs, err := store.MirrorDocs(exported)
+	if err != nil {
+		return err
+	}
+	previous, _ := devdocs.ReadMirrorManifest(dir)
+	for _, doc := range previous {
+		if !slices.Contains(exported, doc.Slug) {
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Slug < docs[j].Slug })
+	data, err := json.MarshalIndent(docs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mirror catalog: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, devdocs.MirrorManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write mirror catalog: %w", err)
+	}
+
+	if len(exportErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d export(s) failed:\n", len(exportErrors))
+		for _, errMsg := range exportErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d export(s) failed (see above)", len(exportErrors))
+	}
+
+	fmt.Printf("\nExported %d doc(s) to %s\n", len(exported), dir)
+	return nil
+}
+
+func runMirrorImport(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	docs, err := devdocs.ReadMirrorManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	slugs := args[1:]
+	if len(slugs) == 0 {
+		for _, doc := range docs {
+			slugs = append(slugs, doc.Slug)
+		}
+	}
+	if len(slugs) == 0 {
+		fmt.Printf("No docs in %s.\n", dir)
+		return nil
+	}
+
+	cfg := config.DefaultPaths()
+	if err := cfg.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+	storeOpts := []devdocs.StoreOption{devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir)}
+	if mirrorEncrypt {
+		if os.Getenv(passphraseEnv) == "" {
+			return fmt.Errorf("--encrypt needs a passphrase in $%s", passphraseEnv)
+		}
+		storeOpts = append(storeOpts, devdocs.WithEncryption(os.Getenv(passphraseEnv)))
+	} else {
+		storeOpts = append(storeOpts, devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+	}
+	storeOpts = append(storeOpts, devdocs.WithCompression(compressDocs), devdocs.WithPostProcess(postProcess, keepOriginals))
+	store := devdocs.NewStore(storeOpts...)
+
+	var importErrors []string
+	var imported []devdocs.Doc
+	for _, input := range slugs {
+		slug, err := devdocs.ResolveSlug(docs, parseDocSlug(input))
+		if err != nil {
+			importErrors = append(importErrors, err.Error())
+			continue
+		}
+		doc, err := importMirrorDoc(store, dir, slug, docs)
+		if err != nil {
+			importErrors = append(importErrors, fmt.Sprintf("failed to import %s: %v", input, err))
+			continue
+		}
+		if doc != nil {
+			imported = append(imported, *doc)
+		}
+	}
+
+	// List imported docs by name even without a DevDocs catalog
+	if err := store.MergeManifest(imported); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not update the cached catalog: %v\n", err)
+	}
+
+	if len(importErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d import(s) failed:\n", len(importErrors))
+		for _, errMsg := range importErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d import(s) failed (see above)", len(importErrors))
+	}
+	return nil
+}
+
+// importMirrorDoc installs one doc from its bundle in dir. It returns nil when
+// the doc is skipped: already installed at the same or a newer build, or pinned.
+func importMirrorDoc(store *devdocs.Store, dir, slug string, docs []devdocs.Doc) (*devdocs.Doc, error) {
+	doc, err := store.ImportMirrorDoc(dir, slug, docs, mirrorForce)
+	switch {
+	case errors.Is(err, devdocs.ErrUpToDate):
+		fmt.Printf("%s is up to date\n", slug)
+		return nil, nil
+	case errors.Is(err, devdocs.ErrPinned):
+		fmt.Printf("%s is pinned, skipping (use --force to import it)\n", slug)
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	entries := 0
+	if index, err := store.LoadIndex(slug); err == nil {
+		entries = len(index.Entries)
+	}
+	fmt.Printf("Imported %s (%d entries)\n", slug, entries)
+	return doc, nil
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// openBrowser opens a URL or local file in the system's default browser.
+func openBrowser(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("opening %s: %w", target, err)
+	}
+	// Don't wait for the browser; release the child process
+	return cmd.Process.Release()
+}
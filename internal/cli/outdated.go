@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+const (
+	// updateCheckInterval is how often the opt-in update check may contact DevDocs.
+	updateCheckInterval = 24 * time.Hour
+
+	// updateCheckWait bounds how long a search waits for the catalog refresh.
+	updateCheckWait = 2 * time.Second
+)
+
+var (
+	outdatedRefresh bool
+
+	// Print a notice after searches when installed docs are outdated
+	updateCheck bool
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List installed docs with newer releases",
+	Long: `Compares installed docs against the DevDocs catalog and lists those with a
+newer release, with the change in download size. Use --refresh to revalidate
+the cached catalog first; upgrade them with "dsearch upgrade".
+
+Set update_check: true in the config (or DSEARCH_UPDATE_CHECK=1) to get a
+one-line notice after searches when docs are outdated.`,
+	Args: cobra.NoArgs,
+	RunE: runOutdated,
+}
+
+func init() {
+	outdatedCmd.Flags().BoolVar(&outdatedRefresh, "refresh", false, "revalidate the cached catalog with DevDocs first")
+}
+
+func runOutdated(cmd *cobra.Command, args []string) error {
+	cfg := config.DefaultPaths()
+	if err := cfg.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir))
+
+	manifest, err := loadManifest(cmd.Context(), newClient(), store, outdatedRefresh)
+	if err != nil {
+		return err
+	}
+
+	updates := store.Outdated(manifest)
+	if len(updates) == 0 {
+		fmt.Println("All installed docs are up to date.")
+		return nil
+	}
+
+	pinned := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSLUG\tINSTALLED\tLATEST\tRELEASE\tSIZE\t")
+	for _, u := range updates {
+		note := ""
+		if u.Meta.Pinned {
+			note = "pinned"
+			pinned++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s (%s)\t%s\n",
+			u.Doc.Name, u.Doc.Slug,
+			formatMtime(u.Meta.Mtime), formatMtime(u.Doc.Mtime), u.Doc.Release,
+			formatBytes(u.Doc.DBSize), formatSizeDelta(u.SizeDelta()), note)
+	}
+	w.Flush()
+
+	if n := len(updates) - pinned; n > 0 {
+		fmt.Printf("\n%d doc(s) can be upgraded with: dsearch upgrade\n", n)
+	}
+	if pinned > 0 {
+		fmt.Printf("%d pinned doc(s) are kept at their release (dsearch upgrade --force to upgrade them)\n", pinned)
+	}
+	return nil
+}
+
+// startUpdateCheck revalidates a stale cached catalog in the background (trying
+// at most once per updateCheckInterval) while a search runs. The returned func waits briefly
+// for the refresh and prints a one-line notice when installed docs are outdated.
+func startUpdateCheck(ctx context.Context, store *devdocs.Store) func() {
+	if !updateCheck {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		if age, err := store.ManifestAge(); err == nil && age < catalogTTL() {
+			return
+		}
+		// Remember the attempt so an offline machine isn't slowed down on every search
+		stamp := filepath.Join(paths.CacheDir, "update-check")
+		if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < updateCheckInterval {
+			return
+		}
+		if err := os.WriteFile(stamp, nil, 0644); err != nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, updateCheckWait)
+		defer cancel()
+		_, _ = refreshManifest(ctx, newClient(devdocs.WithRetries(0)), store)
+	}()
+
+	return func() {
+		<-done
+		manifest, err := store.LoadManifest()
+		if err != nil {
+			return
+		}
+		n := 0
+		for _, u := range store.Outdated(manifest) {
+			if !u.Meta.Pinned {
+				n++
+			}
+		}
+		if n > 0 {
+			fmt.Fprintf(os.Stderr, "\n%d installed doc(s) have newer releases (see dsearch outdated)\n", n)
+		}
+	}
+}
+
+// formatMtime formats a DevDocs mtime (Unix seconds) as a date.
+func formatMtime(mtime int64) string {
+	if mtime <= 0 {
+		return "-"
+	}
+	return time.Unix(mtime, 0).Format("2006-01-02")
+}
+
+// formatSizeDelta formats a size change with an explicit sign.
+func formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + formatBytes(-delta)
+	}
+	return "+" + formatBytes(delta)
+}
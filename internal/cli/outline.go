@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+	"github.com/icampana/dsearch/internal/render"
+)
+
+var outlineCmd = &cobra.Command{
+	Use:   "outline <query>",
+	Short: "Print the heading outline of the best match",
+	Long: `Prints the heading tree of the best matching page with anchor identifiers.
+Jump to a section with 'dsearch show <doc> <path>#<anchor>'.
+
+Examples:
+  dsearch outline net/http -d go
+  dsearch show go net/http/index#Client`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOutline,
+}
+
+func runOutline(cmd *cobra.Command, args []string) error {
+	engine, store, err := loadSearchEngine(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	results, _, err := engine.Search(args[0], nil)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		return nil
+	}
+
+	result := results[0]
+	content, err := store.LoadHTML(result.Slug, result.Path)
+	if err != nil {
+		return fmt.Errorf("reading content: %w", err)
+	}
+	_ = store.RecordUsage(result.Slug)
+
+	headings, err := render.Outline([]byte(content))
+	if err != nil {
+		return fmt.Errorf("building outline: %w", err)
+	}
+
+	page, _ := devdocs.SplitPath(result.Path)
+	fmt.Printf("%s [%s]\n", styled(ansiBold, result.Name), result.Slug)
+	fmt.Printf("  Path: %s\n\n", page)
+
+	if len(headings) == 0 {
+		fmt.Println("No headings found.")
+		return nil
+	}
+
+	// Indent relative to the shallowest heading on the page
+	minLevel := headings[0].Level
+	for _, h := range headings {
+		minLevel = min(minLevel, h.Level)
+	}
+
+	for _, h := range headings {
+		indent := strings.Repeat("  ", h.Level-minLevel)
+		if h.Anchor != "" {
+			fmt.Printf("%s%s  %s\n", indent, h.Text, styled(ansiCyan, "#"+h.Anchor))
+		} else {
+			fmt.Printf("%s%s\n", indent, h.Text)
+		}
+	}
+
+	fmt.Printf("\nShow a section with: dsearch show %s %s#<anchor>\n", result.Slug, page)
+	return nil
+}
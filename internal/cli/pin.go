@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin <doc>...",
+	Short: "Keep installed documentation at its current release",
+	Long: `Pins installed docs so "dsearch upgrade" and reinstalls leave them at their
+current release, e.g. to match the runtime versions used in production.
+Pinned docs are only updated with --force.
+
+Examples:
+  dsearch pin react~18 node~20_lts
+  dsearch unpin react~18`,
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              func(cmd *cobra.Command, args []string) error { return setPinned(args, true) },
+	ValidArgsFunction: completeInstalledDocs,
+}
+
+var unpinCmd = &cobra.Command{
+	Use:               "unpin <doc>...",
+	Short:             "Allow pinned documentation to be upgraded again",
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              func(cmd *cobra.Command, args []string) error { return setPinned(args, false) },
+	ValidArgsFunction: completeInstalledDocs,
+}
+
+// setPinned pins or unpins the docs given as user input.
+func setPinned(args []string, pinned bool) error {
+	cfg := config.DefaultPaths()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir))
+
+	action, done := "pin", "pinned"
+	if !pinned {
+		action, done = "unpin", "unpinned"
+	}
+
+	var pinErrors []string
+	for _, input := range args {
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			pinErrors = append(pinErrors, err.Error())
+			continue
+		}
+
+		if !store.IsInstalled(slug) {
+			pinErrors = append(pinErrors, fmt.Sprintf("doc '%s' is not installed", input))
+			continue
+		}
+		if err := store.SetPinned(slug, pinned); err != nil {
+			pinErrors = append(pinErrors, fmt.Sprintf("failed to %s %s: %v", action, input, err))
+			continue
+		}
+		fmt.Printf("%s %s\n", slug, done)
+	}
+
+	if len(pinErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d doc(s) could not be %s:\n", len(pinErrors), done)
+		for _, errMsg := range pinErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d %s(s) failed (see above)", len(pinErrors), action)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+// progressRedraw limits how often bars are redrawn on byte progress.
+const progressRedraw = 100 * time.Millisecond
+
+// multiProgress renders one progress line per doc. On a terminal the lines are
+// redrawn in place; otherwise each status change is printed as a plain line.
+type multiProgress struct {
+	mu       sync.Mutex
+	out      io.Writer
+	live     bool
+	bars     []*progressBar
+	drawn    int // Lines drawn by the last redraw
+	lastDraw time.Time
+}
+
+// progressBar tracks one doc's install.
+type progressBar struct {
+	mp       *multiProgress
+	label    string
+	status   string
+	done     int64
+	total    int64
+	pages    bool // done and total count pages rather than bytes
+	finished bool
+}
+
+// newMultiProgress creates a progress display writing to out.
+func newMultiProgress(out io.Writer) *multiProgress {
+	f, ok := out.(*os.File)
+	return &multiProgress{
+		out:  out,
+		live: ok && isTerminal(f) && !dumbTerminal(),
+	}
+}
+
+// Add registers a new bar.
+func (m *multiProgress) Add(label string) *progressBar {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := &progressBar{mp: m, label: label, total: -1}
+	m.bars = append(m.bars, b)
+	return b
+}
+
+// SetStatus updates the bar's status text (e.g. "fetching index").
+func (b *progressBar) SetStatus(status string) {
+	m := b.mp
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b.status = status
+	b.done, b.total, b.pages = 0, -1, false
+	if !m.live {
+		fmt.Fprintf(m.out, "%s: %s\n", b.label, status)
+		return
+	}
+	m.redraw()
+}
+
+// SetProgress updates the bar's byte progress in a phase of a fetch, as a
+// devdocs.ProgressFunc. total is -1 when unknown.
+func (b *progressBar) SetProgress(phase devdocs.Phase, done, total int64) {
+	b.update(string(phase), done, total, false)
+}
+
+// SetPages updates the bar's progress in pages.
+func (b *progressBar) SetPages(status string, done, total int64) {
+	b.update(status, done, total, true)
+}
+
+// update records progress, redrawing at most every progressRedraw unless the
+// status changed.
+func (b *progressBar) update(status string, done, total int64, pages bool) {
+	m := b.mp
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := status != b.status
+	b.status = status
+	b.done, b.total, b.pages = done, total, pages
+	switch {
+	case !m.live:
+		if changed {
+			fmt.Fprintf(m.out, "%s: %s\n", b.label, status)
+		}
+	case changed || time.Since(m.lastDraw) >= progressRedraw:
+		m.redraw()
+	}
+}
+
+// Done marks the bar finished with a final status.
+func (b *progressBar) Done(status string) {
+	m := b.mp
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b.status = status
+	b.finished = true
+	if !m.live {
+		fmt.Fprintf(m.out, "%s: %s\n", b.label, status)
+		return
+	}
+	m.redraw()
+}
+
+// redraw repaints all bars in place. Callers must hold m.mu.
+func (m *multiProgress) redraw() {
+	var sb strings.Builder
+	if m.drawn > 0 {
+		fmt.Fprintf(&sb, "\x1b[%dA", m.drawn) // Move up to the first bar
+	}
+
+	width := 0
+	for _, b := range m.bars {
+		width = max(width, len(b.label))
+	}
+	for _, b := range m.bars {
+		sb.WriteString("\r\x1b[K")
+		sb.WriteString(b.render(width))
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprint(m.out, sb.String())
+	m.drawn = len(m.bars)
+	m.lastDraw = time.Now()
+}
+
+// render formats the bar as a single line.
+func (b *progressBar) render(width int) string {
+	line := fmt.Sprintf("%-*s  ", width, b.label)
+	switch {
+	case b.finished:
+		return line + b.status
+	case b.total > 0 && b.done <= b.total:
+		const barWidth = 24
+		filled := int(float64(barWidth) * float64(b.done) / float64(b.total))
+		filled = min(max(filled, 0), barWidth)
+		amount := fmt.Sprintf("%s/%s", formatBytes(b.done), formatBytes(b.total))
+		if b.pages {
+			amount = fmt.Sprintf("%d/%d pages", b.done, b.total)
+		}
+		return line + fmt.Sprintf("[%s%s] %3d%%  %s  %s",
+			strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled),
+			min(b.done*100/b.total, 100), amount, b.status)
+	case b.done > 0 && b.pages:
+		// Past the expected total (e.g. a wrong size in the catalog), only count
+		return line + fmt.Sprintf("%d pages  %s", b.done, b.status)
+	case b.done > 0:
+		return line + fmt.Sprintf("%s  %s", formatBytes(b.done), b.status)
+	default:
+		return line + b.status
+	}
+}
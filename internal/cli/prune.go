@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var (
+	pruneMaxSize string
+	pruneYes     bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove least recently used docs to stay under the disk quota",
+	Long: `Lists the least recently used docs that need to be removed for the installed
+docs to fit in the disk quota (max_size in the config, or --max-size), then
+removes them after confirmation. Pinned docs are never removed.
+
+A doc counts as used when it has the best match of a search, or when it is
+read with "dsearch show" or "dsearch outline".
+
+Examples:
+  dsearch prune                  # Suggest docs to remove, then ask
+  dsearch prune --max-size 1GB   # Use a different quota
+  dsearch prune --yes            # Remove without asking`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().StringVar(&pruneMaxSize, "max-size", "", "disk quota, e.g. 500MB or 2GiB (default from the max_size config key)")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "remove the docs without asking")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	limit := maxSize
+	if pruneMaxSize != "" {
+		n, err := config.ParseSize(pruneMaxSize)
+		if err != nil {
+			return err
+		}
+		limit = n
+	}
+	if limit <= 0 {
+		return fmt.Errorf("no disk quota: set max_size in the config or pass --max-size")
+	}
+
+	// Initialize paths
+	cfg := config.DefaultPaths()
+	if err := cfg.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir))
+
+	usage, err := store.Usage()
+	if err != nil {
+		return err
+	}
+	candidates, total := devdocs.PruneCandidates(usage, limit, nil)
+	if total <= limit {
+		fmt.Printf("Installed docs use %s of %s, nothing to prune.\n", formatBytes(total), formatBytes(limit))
+		return nil
+	}
+	if len(candidates) == 0 {
+		fmt.Printf("Installed docs use %s of %s, but only pinned docs are left.\n", formatBytes(total), formatBytes(limit))
+		return nil
+	}
+
+	fmt.Printf("Installed docs use %s of %s. Least recently used docs to remove:\n\n", formatBytes(total), formatBytes(limit))
+	printPruneCandidates(os.Stdout, candidates)
+	fmt.Println()
+
+	if !pruneYes && !confirm(fmt.Sprintf("Remove %d doc(s)?", len(candidates))) {
+		fmt.Println("Nothing removed. Run with --yes to remove them.")
+		return nil
+	}
+	return pruneDocs(store, candidates, os.Stdout)
+}
+
+// printPruneCandidates lists docs to prune with their size and last use.
+func printPruneCandidates(out io.Writer, candidates []devdocs.DocUsage) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SLUG\tSIZE\tLAST USED")
+	for _, doc := range candidates {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", doc.Slug, formatBytes(doc.Size), doc.LastUsed.Format("2006-01-02"))
+	}
+	w.Flush()
+}
+
+// pruneDocs uninstalls the given docs, reporting failures together.
+func pruneDocs(store *devdocs.Store, docs []devdocs.DocUsage, out io.Writer) error {
+	var pruneErrors []string
+	var freed int64
+	for _, doc := range docs {
+		if err := store.Uninstall(doc.Slug); err != nil {
+			pruneErrors = append(pruneErrors, fmt.Sprintf("failed to remove %s: %v", doc.Slug, err))
+			continue
+		}
+		fmt.Fprintf(out, "Removed %s (%s)\n", doc.Slug, formatBytes(doc.Size))
+		freed += doc.Size
+	}
+	if freed > 0 {
+		fmt.Fprintf(out, "Freed %s\n", formatBytes(freed))
+	}
+
+	if len(pruneErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d removal(s) failed:\n", len(pruneErrors))
+		for _, errMsg := range pruneErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d removal(s) failed (see above)", len(pruneErrors))
+	}
+	return nil
+}
+
+// enforceQuota checks installed docs against max_size after installing. Over
+// the quota, it either evicts least recently used docs (auto_prune) or warns.
+// The docs just installed are never evicted.
+func enforceQuota(store *devdocs.Store, installed []string, out io.Writer) error {
+	if maxSize <= 0 {
+		return nil
+	}
+
+	usage, err := store.Usage()
+	if err != nil {
+		return err
+	}
+	keep := make(map[string]bool, len(installed))
+	for _, slug := range installed {
+		keep[slug] = true
+	}
+	candidates, total := devdocs.PruneCandidates(usage, maxSize, keep)
+	if total <= maxSize {
+		return nil
+	}
+
+	if autoPrune && len(candidates) > 0 {
+		fmt.Fprintf(out, "\nInstalled docs use %s, over the %s quota. Removing least recently used docs:\n", formatBytes(total), formatBytes(maxSize))
+		return pruneDocs(store, candidates, out)
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s installed docs use %s, over the %s quota (max_size).", warningPrefix(), formatBytes(total), formatBytes(maxSize))
+	if len(candidates) > 0 {
+		fmt.Fprintf(os.Stderr, " Run \"dsearch prune\" to remove least recently used docs.")
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
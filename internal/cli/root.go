@@ -1,9 +1,17 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -17,11 +25,46 @@ var (
 	// Global flags
 	cfgFile    string
 	docs       []string
+	exclude    []string
+	types      []string
 	format     string
 	limit      int
 	listOnly   bool
 	full       bool
+	maxLength  int
+	colorMode  string
 	jsonOutput bool
+	openResult bool
+	lowMemory  bool
+	online     bool
+
+	// Append the doc's copyright and license notice to rendered content
+	attribution bool
+
+	// Docs to install on first use when nothing is installed (non-interactive only)
+	autoInstall []string
+
+	// Store the content of new installs compressed
+	compressDocs bool
+
+	// Install-time post-processing steps by doc slug or name, and whether
+	// the original HTML of processed pages is kept
+	postProcess   map[string][]string
+	keepOriginals bool
+
+	// Disk quota for installed docs in bytes (0 for none), and whether
+	// installs evict least recently used docs to stay under it
+	maxSize   int64
+	autoPrune bool
+
+	// Base URL of prebuilt doc bundles, tried before DevDocs when installing
+	bundleURL string
+
+	// DevDocs client settings (mirrors, timeout, User-Agent)
+	manifestURL string
+	contentURL  string
+	httpTimeout time.Duration
+	userAgent   string
 
 	// Paths for XDG directories
 	paths config.Paths
@@ -39,38 +82,80 @@ in your terminal. Supports fuzzy matching and multiple output formats.
 Examples:
   dsearch useState              # Search for "useState" in all installed docs
   dsearch useState -d react    # Search only in React documentation
+  dsearch map -D cpp -D dom    # Search all docs except C++ and DOM
+  dsearch use -t hook          # Only entries of a given type (e.g. Hook, Function)
   dsearch useState --format md # Output as markdown
-  dsearch useState --json      # Output results as JSON`,
-	RunE: runSearch,
-	Args: cobra.MaximumNArgs(1),
+  dsearch useState --json      # Output results as JSON
+  dsearch useState --open      # Open the best match in the browser
+  dsearch useState --open --online  # Open the upstream web page`,
+	PersistentPreRunE: initConfig,
+	RunE:              runSearch,
+	Args:              cobra.MaximumNArgs(1),
 }
 
 // Execute adds all child commands to root command and sets flags appropriately.
+// Ctrl+C cancels the command's context so downloads stop cleanly.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-
 	// Persistent flags (available to all commands)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: $XDG_CONFIG_HOME/dsearch/config.yaml)")
 	rootCmd.PersistentFlags().StringSliceVarP(&docs, "doc", "d", nil, "filter to specific doc(s)")
-	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "text", "output format: text, md")
+	rootCmd.PersistentFlags().StringSliceVarP(&exclude, "exclude-doc", "D", nil, "exclude specific doc(s) from the search")
+	rootCmd.PersistentFlags().StringSliceVarP(&types, "type", "t", nil, "filter to entry type(s), e.g. function, class, hook")
 	rootCmd.PersistentFlags().IntVarP(&limit, "limit", "l", 10, "maximum number of results")
 	rootCmd.PersistentFlags().BoolVar(&listOnly, "list", false, "list results only, don't show content")
-	rootCmd.PersistentFlags().BoolVar(&full, "full", false, "show full content without truncation")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "colorize output: auto, always, never")
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output results as JSON")
+	rootCmd.PersistentFlags().BoolVar(&lowMemory, "low-memory", false, "load one doc index at a time to reduce memory use")
+	addRenderFlags(rootCmd)
+	rootCmd.Flags().BoolVar(&openResult, "open", false, "open the best match in the browser")
+	rootCmd.Flags().BoolVar(&online, "online", false, "with --open, open the upstream web page instead of the local copy")
 
 	// Add subcommands
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(availableCmd)
 	rootCmd.AddCommand(installCmd)
 	rootCmd.AddCommand(uninstallCmd)
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(compressCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(upgradeCmd)
+	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(mirrorCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(dumpCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(outlineCmd)
+	rootCmd.AddCommand(bookmarksCmd)
+	rootCmd.AddCommand(scrapbookCmd)
+
+	// Complete --doc with installed doc slugs
+	_ = rootCmd.RegisterFlagCompletionFunc("doc", completeInstalledDocs)
+	_ = rootCmd.RegisterFlagCompletionFunc("exclude-doc", completeInstalledDocs)
 }
 
-func initConfig() {
+// addRenderFlags adds the content rendering flags to a command that displays entries.
+// They are not persistent so subcommands like install can use -f for other purposes.
+func addRenderFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "output format: text, md")
+	cmd.Flags().BoolVar(&full, "full", false, "show full content without truncation")
+	cmd.Flags().IntVar(&maxLength, "max-length", 2000, "truncate content to this many characters")
+	cmd.Flags().BoolVar(&attribution, "attribution", false, "append the doc's copyright and license notice")
+}
+
+func initConfig(cmd *cobra.Command, args []string) error {
 	paths = config.DefaultPaths()
 	if err := paths.EnsureDirs(); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not create directories: %v\n", err)
@@ -80,18 +165,94 @@ func initConfig() {
 	if err := config.MigrateDataDir(paths.DataDir); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: migration failed: %v\n", err)
 	}
+
+	// Load config file (explicit --config must exist)
+	path := paths.ConfigFile()
+	if cfgFile != "" {
+		path = cfgFile
+	}
+	cfg, err := config.Load(path, cfgFile != "")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	// Flags explicitly set on the command line win over env and config
+	flags := cmd.Flags()
+	if !flags.Changed("format") {
+		format = cfg.Format
+	}
+	if !flags.Changed("limit") {
+		limit = cfg.Limit
+	}
+	if !flags.Changed("doc") {
+		docs = cfg.Docs
+	}
+	if !flags.Changed("full") {
+		full = cfg.Full
+	}
+	if !flags.Changed("max-length") {
+		maxLength = cfg.MaxLength
+	}
+	if !flags.Changed("attribution") {
+		attribution = cfg.Attribution
+	}
+	if !flags.Changed("color") {
+		colorMode = cfg.Color
+	}
+	if !flags.Changed("low-memory") {
+		lowMemory = cfg.LowMemory
+	}
+	autoInstall = cfg.AutoInstall
+	bundleURL = cfg.BundleURL
+	compressDocs = cfg.Compress
+	postProcess = cfg.PostProcess
+	keepOriginals = cfg.KeepOriginals
+	autoPrune = cfg.AutoPrune
+	maxSize = 0
+	if cfg.MaxSize != "" {
+		// Already checked by Validate
+		maxSize, _ = config.ParseSize(cfg.MaxSize)
+	}
+	updateCheck = cfg.UpdateCheck
+	manifestURL = cfg.ManifestURL
+	contentURL = cfg.ContentURL
+	httpTimeout = cfg.Timeout
+	userAgent = cfg.UserAgent
+	manifestTTL = cfg.ManifestTTL
+
+	// Small containers and VMs switch to low-memory mode automatically
+	if memLimit := config.MemoryLimit(); memLimit > 0 {
+		if memLimit < config.LowMemoryThreshold && !flags.Changed("low-memory") {
+			lowMemory = true
+		}
+		if lowMemory {
+			// Collect garbage more aggressively as the container limit approaches
+			debug.SetMemoryLimit(memLimit * 9 / 10)
+		}
+	}
+	return nil
 }
 
-func loadSearchEngine() (*search.Engine, *devdocs.Store, error) {
-	store := devdocs.NewStore(paths.DataDir, paths.CacheDir)
+// resolveDocs returns the installed slugs selected by the --doc and --exclude-doc filters.
+// All installed docs are selected when no --doc filter is given.
+func resolveDocs(ctx context.Context, store *devdocs.Store) ([]string, error) {
 	installedSlugs := store.ListInstalled()
 
+	// Bootstrap Docker images and CI runs, where interactive setup isn't possible
+	if len(installedSlugs) == 0 && len(autoInstall) > 0 && !isTerminal(os.Stdin) {
+		fmt.Fprintf(os.Stderr, "No documentation installed, auto-installing %s...\n", strings.Join(autoInstall, ", "))
+		if err := installDocs(ctx, store, autoInstall, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: auto-install failed: %v\n", err)
+		}
+		installedSlugs = store.ListInstalled()
+	}
+
 	if len(installedSlugs) == 0 {
-		return nil, nil, fmt.Errorf("no documentation installed. Run 'dsearch install <doc>' to install documentation")
+		return nil, fmt.Errorf("no documentation installed. Run 'dsearch install <doc>' to install documentation")
 	}
 
 	// Optimization: If user specified docs, only load those
-	slugsToLoad := installedSlugs
+	selected := installedSlugs
 	if len(docs) > 0 {
 		// Verify requested docs are installed
 		validSlug := make(map[string]bool)
@@ -101,6 +262,10 @@ func loadSearchEngine() (*search.Engine, *devdocs.Store, error) {
 
 		filtered := make([]string, 0)
 		for _, d := range docs {
+			d, err := resolveInstalledSlug(store, d)
+			if err != nil {
+				return nil, err
+			}
 			if validSlug[d] {
 				filtered = append(filtered, d)
 			} else {
@@ -108,10 +273,55 @@ func loadSearchEngine() (*search.Engine, *devdocs.Store, error) {
 			}
 		}
 		if len(filtered) > 0 {
-			slugsToLoad = filtered
+			selected = filtered
 		}
 	}
 
+	if len(exclude) == 0 {
+		return selected, nil
+	}
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, d := range exclude {
+		d, err := resolveInstalledSlug(store, d)
+		if err != nil {
+			return nil, err
+		}
+		excluded[d] = true
+	}
+
+	remaining := make([]string, 0, len(selected))
+	for _, slug := range selected {
+		if !excluded[slug] {
+			remaining = append(remaining, slug)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil, fmt.Errorf("all selected docs are excluded by --exclude-doc")
+	}
+	return remaining, nil
+}
+
+// passphraseEnv names the environment variable holding the passphrase for
+// docs encrypted at rest.
+const passphraseEnv = "DSEARCH_PASSPHRASE"
+
+func loadSearchEngine(ctx context.Context) (*search.Engine, *devdocs.Store, error) {
+	store := devdocs.NewStore(devdocs.WithDataDir(paths.DataDir), devdocs.WithCacheDir(paths.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)),
+		devdocs.WithCompression(compressDocs), devdocs.WithPostProcess(postProcess, keepOriginals))
+
+	slugsToLoad, err := resolveDocs(ctx, store)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Low-memory mode loads each index only while it is being searched
+	if lowMemory {
+		engine := search.NewLazy(slugsToLoad, store.LoadIndex, limit)
+		engine.SetTypeFilter(types)
+		return engine, store, nil
+	}
+
 	allIndices := make([]*devdocs.Index, 0, len(slugsToLoad))
 	indicesBySlug := make(map[string]*devdocs.Index, len(slugsToLoad))
 
@@ -129,7 +339,10 @@ func loadSearchEngine() (*search.Engine, *devdocs.Store, error) {
 		return nil, nil, fmt.Errorf("no documentation could be loaded")
 	}
 
-	return search.New(allIndices, indicesBySlug, limit), store, nil
+	engine := search.New(allIndices, indicesBySlug, limit)
+	engine.SetTypeFilter(types)
+
+	return engine, store, nil
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
@@ -138,24 +351,30 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize search engine just-in-time
-	engine, store, err := loadSearchEngine()
+	engine, store, err := loadSearchEngine(cmd.Context())
 	if err != nil {
 		return err
 	}
 
+	if !jsonOutput {
+		defer startUpdateCheck(cmd.Context(), store)()
+	}
+
 	query := args[0]
 
 	// Perform search
 	// Pass nil for docs because we already filtered at load time (optimization)
 	results, warning, err := engine.Search(query, nil)
+	if warning != "" && !jsonOutput {
+		for _, line := range strings.Split(warning, "\n") {
+			fmt.Fprintf(os.Stderr, "%s %s\n", warningPrefix(), line)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
 	if err != nil {
 		return err
 	}
 
-	if warning != "" && !jsonOutput {
-		fmt.Fprintf(os.Stderr, "⚠️  %s\n\n", warning)
-	}
-
 	if jsonOutput {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -174,37 +393,106 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 	// Display best match
 	result := results[0]
-	fmt.Printf("\n%s [%s]\n", result.Name, result.Type)
+	_ = store.RecordUsage(result.Slug)
+
+	if openResult {
+		target := result.URL
+		// Encrypted, compressed or markdown pages are unreadable to the browser, so those open online
+		file := store.HTMLPath(result.Slug, result.Path)
+		if !online && file != "" && !store.IsEncrypted(result.Slug) && !store.IsCompressed(result.Slug) {
+			target = localFileURL(file, result.Path)
+		}
+		fmt.Fprintf(os.Stderr, "Opening %s\n", target)
+		return openBrowser(target)
+	}
+
+	fmt.Printf("\n%s [%s]\n", styled(ansiBold, result.Name), styled(ansiCyan, result.Type))
 	fmt.Printf("  Doc: %s\n", result.Slug)
 	fmt.Printf("  Score: %.2f\n", result.Score)
 	fmt.Printf("  Path: %s\n", result.Path)
+	fmt.Printf("  URL: %s\n", result.URL)
 	fmt.Println("\n--- Content ---")
 
-	content, err := store.LoadContent(result.Slug, result.Path)
+	rendered, err := renderContent(store, result.Slug, result.Path)
 	if err != nil {
-		return fmt.Errorf("reading content: %w", err)
+		return err
 	}
 
-	renderer := render.New(render.Format(format))
-	rendered, err := renderer.Render([]byte(content))
+	fmt.Println(rendered)
+	return nil
+}
+
+// renderContent loads an entry's HTML and renders it in the selected format,
+// truncating it unless --full is set. Paths with an #anchor render only that section.
+// With --attribution, the doc's copyright and license notice follows the content.
+func renderContent(store *devdocs.Store, slug, path string) (string, error) {
+	rendered, err := renderEntry(store, slug, path, render.Format(format))
 	if err != nil {
-		return fmt.Errorf("rendering content: %w", err)
+		return "", err
 	}
 
-	maxLength := 2000
-	if full {
-		maxLength = len(rendered)
+	if !full && len(rendered) > maxLength {
+		rendered = rendered[:maxLength] + "\n\n... (truncated)"
+	}
+	if attribution {
+		if notice := attributionNotice(store, slug); notice != "" {
+			rendered += "\n\n---\n" + notice
+		}
 	}
 
-	if len(rendered) > maxLength {
-		rendered = rendered[:maxLength]
-		if !full {
-			rendered = rendered + "\n\n... (truncated)"
+	return rendered, nil
+}
+
+// renderEntry renders an entry in the given format. Pages pre-rendered to
+// markdown at install time are shown as stored in either format, unless a
+// section is requested and their original HTML was kept.
+func renderEntry(store *devdocs.Store, slug, path string, f render.Format) (string, error) {
+	if store.IsMarkdown(slug) {
+		_, anchor := devdocs.SplitPath(path)
+		if anchor == "" || store.HTMLPath(slug, path) == "" {
+			if anchor != "" {
+				fmt.Fprintf(os.Stderr, "Warning: #%s needs the original HTML (see keep_originals), showing the full page\n", anchor)
+			}
+			content, err := store.LoadContent(slug, path)
+			if err != nil {
+				return "", fmt.Errorf("reading content: %w", err)
+			}
+			return content, nil
 		}
 	}
 
-	fmt.Println(rendered)
-	return nil
+	content, err := entryContent(store, slug, path)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := render.New(f).Render([]byte(content))
+	if err != nil {
+		return "", fmt.Errorf("rendering content: %w", err)
+	}
+	return rendered, nil
+}
+
+// entryContent loads an entry's HTML, narrowed to its section when the path has an #anchor.
+func entryContent(store *devdocs.Store, slug, path string) (string, error) {
+	content, err := store.LoadHTML(slug, path)
+	if err != nil {
+		return "", fmt.Errorf("reading content: %w", err)
+	}
+
+	// Narrow to the entry's section when the path has an #anchor
+	if _, anchor := devdocs.SplitPath(path); anchor != "" {
+		section, err := render.Section([]byte(content), anchor)
+		switch {
+		case err == nil:
+			content = string(section)
+		case errors.Is(err, render.ErrAnchorNotFound):
+			fmt.Fprintf(os.Stderr, "Warning: #%s not found, showing the full page\n", anchor)
+		default:
+			return "", fmt.Errorf("extracting section: %w", err)
+		}
+	}
+
+	return content, nil
 }
 
 func printResultList(results []search.Result) {
@@ -235,3 +523,24 @@ func printResultList(results []search.Result) {
 		)
 	}
 }
+
+// completeInstalledDocs completes doc slugs from the installed docs.
+func completeInstalledDocs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := config.DefaultPaths()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir))
+	return store.ListInstalled(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// localFileURL builds a file:// URL for a content file, keeping the entry's #anchor.
+func localFileURL(file, path string) string {
+	p := filepath.ToSlash(file)
+	if !strings.HasPrefix(p, "/") {
+		// Windows drive paths need a leading slash (file:///C:/...)
+		p = "/" + p
+	}
+	u := url.URL{Scheme: "file", Path: p}
+	if _, anchor := devdocs.SplitPath(path); anchor != "" {
+		u.Fragment = anchor
+	}
+	return u.String()
+}
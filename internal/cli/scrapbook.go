@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/render"
+	"github.com/icampana/dsearch/internal/scrapbook"
+)
+
+var (
+	scrapbookFile   string
+	scrapbookOutput string
+	scrapbookTitle  string
+)
+
+var scrapbookCmd = &cobra.Command{
+	Use:   "scrapbook",
+	Short: "Collect documentation snippets into a project scrapbook",
+	Long: `Collects rendered documentation entries, with source attribution, into a
+markdown scrapbook file in the current directory (` + scrapbook.DefaultFile + `).
+
+Examples:
+  dsearch scrapbook add useState -d react   # Append the best match
+  dsearch scrapbook show                    # List captured snippets
+  dsearch scrapbook show 1                  # Print snippet #1
+  dsearch scrapbook export -o notes.md      # Export with a table of contents`,
+	Args: cobra.NoArgs,
+	RunE: runScrapbookShow,
+}
+
+var scrapbookAddCmd = &cobra.Command{
+	Use:   "add <query>",
+	Short: "Append the best match for a query to the scrapbook",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScrapbookAdd,
+}
+
+var scrapbookShowCmd = &cobra.Command{
+	Use:   "show [n]",
+	Short: "List scrapbook snippets, or print one by its number",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runScrapbookShow,
+}
+
+var scrapbookExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the scrapbook as a single markdown document",
+	Args:  cobra.NoArgs,
+	RunE:  runScrapbookExport,
+}
+
+func init() {
+	scrapbookCmd.PersistentFlags().StringVar(&scrapbookFile, "file", scrapbook.DefaultFile, "scrapbook file")
+	scrapbookExportCmd.Flags().StringVarP(&scrapbookOutput, "output", "o", "", "write to file instead of stdout")
+	scrapbookExportCmd.Flags().StringVar(&scrapbookTitle, "title", "Scrapbook", "document title")
+	scrapbookCmd.AddCommand(scrapbookAddCmd)
+	scrapbookCmd.AddCommand(scrapbookShowCmd)
+	scrapbookCmd.AddCommand(scrapbookExportCmd)
+}
+
+func runScrapbookAdd(cmd *cobra.Command, args []string) error {
+	engine, store, err := loadSearchEngine(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	results, _, err := engine.Search(args[0], nil)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no results found for %q", args[0])
+	}
+	result := results[0]
+
+	// Snippets are always captured as full markdown, regardless of --format
+	rendered, err := renderEntry(store, result.Slug, result.Path, render.FormatMD)
+	if err != nil {
+		return err
+	}
+
+	snippet := scrapbook.Snippet{
+		Name:    result.Name,
+		Slug:    result.Slug,
+		Path:    result.Path,
+		URL:     result.URL,
+		Content: rendered,
+	}
+	if err := scrapbook.Append(scrapbookFile, snippet); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added %s (%s) to %s\n", result.Name, result.Slug, scrapbookFile)
+	return nil
+}
+
+func runScrapbookShow(cmd *cobra.Command, args []string) error {
+	snippets, err := scrapbook.Load(scrapbookFile)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > len(snippets) {
+			return fmt.Errorf("no snippet #%s (run 'dsearch scrapbook show' to list them)", args[0])
+		}
+		fmt.Print(snippets[n-1].Markdown())
+		return nil
+	}
+
+	if len(snippets) == 0 {
+		fmt.Printf("No snippets in %s yet.\n", scrapbookFile)
+		fmt.Println("\nTo capture the best match for a query, run:")
+		fmt.Println("  dsearch scrapbook add <query>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tNAME\tDOC\tPATH\tADDED")
+	for i, s := range snippets {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, s.Name, s.Slug, s.Path, s.Added.Local().Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func runScrapbookExport(cmd *cobra.Command, args []string) error {
+	snippets, err := scrapbook.Load(scrapbookFile)
+	if err != nil {
+		return err
+	}
+	if len(snippets) == 0 {
+		return fmt.Errorf("no snippets in %s", scrapbookFile)
+	}
+
+	out := scrapbook.Export(scrapbookTitle, snippets)
+	if scrapbookOutput == "" {
+		fmt.Print(out)
+		return nil
+	}
+
+	if err := os.WriteFile(scrapbookOutput, []byte(out), 0644); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	fmt.Printf("Exported %d snippet(s) to %s\n", len(snippets), scrapbookOutput)
+	return nil
+}
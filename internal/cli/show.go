@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <doc> <path> | show -",
+	Short: "Show the content of a specific entry",
+	Long: `Shows the content of an entry by doc slug and path.
+
+With '-', reads a selection from stdin: either a line produced by 'dsearch dump'
+(name, type, doc, path separated by tabs) or a "<doc> <path>" pair.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runShow,
+}
+
+func init() {
+	addRenderFlags(showCmd)
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	var slug, path string
+
+	switch {
+	case len(args) == 1 && args[0] == "-":
+		var err error
+		slug, path, err = readSelection(cmd)
+		if err != nil {
+			return err
+		}
+	case len(args) == 2:
+		slug, path = args[0], args[1]
+	default:
+		return fmt.Errorf("expected <doc> <path> or '-'")
+	}
+
+	store := devdocs.NewStore(devdocs.WithDataDir(paths.DataDir), devdocs.WithCacheDir(paths.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+	slug, err := resolveInstalledSlug(store, slug)
+	if err != nil {
+		return err
+	}
+	if !store.IsInstalled(slug) {
+		return fmt.Errorf("doc '%s' is not installed", slug)
+	}
+
+	rendered, err := renderContent(store, slug, path)
+	if err != nil {
+		return err
+	}
+	_ = store.RecordUsage(slug)
+
+	fmt.Println(rendered)
+	return nil
+}
+
+// readSelection parses the first non-empty line of stdin into a doc slug and path.
+func readSelection(cmd *cobra.Command) (string, string, error) {
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Dump format: name, type, slug, path
+		if fields := strings.Split(line, "\t"); len(fields) == 4 {
+			return fields[2], fields[3], nil
+		}
+		if fields := strings.Fields(line); len(fields) == 2 {
+			return fields[0], fields[1], nil
+		}
+		return "", "", fmt.Errorf("invalid selection %q", line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("reading selection: %w", err)
+	}
+	return "", "", fmt.Errorf("no selection on stdin")
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	ansiBold = "1"
+	ansiCyan = "36"
+)
+
+// isTerminal reports whether f is attached to a character device (a terminal).
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// dumbTerminal reports whether TERM declares a terminal without escape sequence support.
+func dumbTerminal() bool {
+	term := os.Getenv("TERM")
+	return term == "dumb" || (term == "" && runtime.GOOS != "windows")
+}
+
+// supportsUnicode reports whether the locale can display non-ASCII symbols.
+// Uses the first of LC_ALL, LC_CTYPE and LANG that is set, like setlocale(3).
+func supportsUnicode() bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	if dumbTerminal() {
+		return false
+	}
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
+
+// useColor reports whether ANSI styling should be applied to stdout.
+func useColor() bool {
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return isTerminal(os.Stdout) && !dumbTerminal()
+}
+
+// styled wraps s in the given ANSI SGR code when color is enabled.
+func styled(code, s string) string {
+	if !useColor() {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// warningPrefix returns the marker printed before warnings, falling back to
+// plain ASCII on terminals without Unicode support.
+func warningPrefix() string {
+	if supportsUnicode() {
+		return "⚠️ "
+	}
+	return "Warning:"
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// It returns false without asking when stdin is not a terminal.
+func confirm(prompt string) bool {
+	if !isTerminal(os.Stdin) {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// choose lists numbered options on stderr and reads the number picked from
+// stdin. It returns -1 when nothing is picked or stdin is not a terminal.
+func choose(prompt string, options []string) int {
+	if !isTerminal(os.Stdin) {
+		return -1
+	}
+	fmt.Fprintln(os.Stderr, prompt)
+	for i, option := range options {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, option)
+	}
+	fmt.Fprintf(os.Stderr, "Pick a number (Enter to skip): ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 || n > len(options) {
+		return -1
+	}
+	return n - 1
+}
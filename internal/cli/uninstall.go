@@ -25,13 +25,17 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create directories: %w", err)
 	}
 
-	store := devdocs.NewStore(cfg.DataDir, cfg.CacheDir)
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir))
 
 	var uninstallErrors []string
 	successCount := 0
 
 	for _, input := range args {
-		slug := parseDocSlug(input)
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			uninstallErrors = append(uninstallErrors, err.Error())
+			continue
+		}
 
 		if !store.IsInstalled(slug) {
 			uninstallErrors = append(uninstallErrors, fmt.Sprintf("doc '%s' is not installed", input))
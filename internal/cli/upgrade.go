@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+// digestPreview is the number of entries listed per section when printing a digest.
+const digestPreview = 15
+
+var (
+	digestFile   string
+	upgradeForce bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [doc]...",
+	Short: "Upgrade installed documentation and show what's new",
+	Long: `Refreshes the DevDocs catalog and reinstalls every installed doc (or the
+given docs) that has a newer release upstream. Pinned docs (see "dsearch pin")
+are skipped unless --force is given. Pages that didn't change are carried over
+from the installed copy, so only changed pages are written.
+
+After upgrading, prints a digest of the entries added and removed in each doc,
+so you learn about new APIs in the libraries you track.
+
+Examples:
+  dsearch upgrade                        # Upgrade all installed docs
+  dsearch upgrade react go               # Upgrade specific docs
+  dsearch upgrade --digest whatsnew.md   # Also save the full digest`,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "also upgrade pinned docs")
+	upgradeCmd.Flags().StringVar(&digestFile, "digest", "", "save the full what's-new digest as markdown to this file")
+}
+
+// docDigest records what changed in one doc during an upgrade.
+type docDigest struct {
+	Slug    string
+	Name    string
+	Release string
+	Diff    devdocs.IndexDiff
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	// Initialize paths
+	cfg := config.DefaultPaths()
+	if err := cfg.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+
+	client := newClient()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)),
+		devdocs.WithPostProcess(postProcess, keepOriginals))
+
+	slugs := make([]string, 0, len(args))
+	for _, input := range args {
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			return err
+		}
+		slugs = append(slugs, slug)
+	}
+	if len(slugs) == 0 {
+		slugs = store.ListInstalled()
+	}
+	if len(slugs) == 0 {
+		fmt.Println("No documentation installed.")
+		return nil
+	}
+
+	// Always revalidate the catalog so new releases are visible
+	manifest, err := loadManifest(cmd.Context(), client, store, true)
+	if err != nil {
+		return err
+	}
+
+	docsBySlug := make(map[string]*devdocs.Doc, len(manifest))
+	for i := range manifest {
+		docsBySlug[manifest[i].Slug] = &manifest[i]
+	}
+
+	var digests []docDigest
+	var upgradeErrors []string
+	progress := newMultiProgress(os.Stdout)
+
+	for _, slug := range slugs {
+		if !store.IsInstalled(slug) {
+			upgradeErrors = append(upgradeErrors, fmt.Sprintf("doc '%s' is not installed", slug))
+			continue
+		}
+		doc, ok := docsBySlug[slug]
+		if !ok {
+			upgradeErrors = append(upgradeErrors, fmt.Sprintf("doc '%s' not found in DevDocs catalog", slug))
+			continue
+		}
+
+		meta, err := store.LoadMeta(slug)
+		if err == nil && meta.Mtime >= doc.Mtime {
+			fmt.Printf("%s is up to date\n", doc.Name)
+			continue
+		}
+		if err == nil && meta.Pinned && !upgradeForce {
+			fmt.Printf("%s is pinned, skipping (use --force to upgrade it)\n", doc.Name)
+			continue
+		}
+
+		bar := progress.Add(fmt.Sprintf("%s %s", doc.Name, doc.Release))
+		bar.SetStatus(fmt.Sprintf("upgrading (%s)", formatBytes(doc.DBSize)))
+
+		// Keep the previous index to compute the digest
+		prev, err := store.LoadIndex(slug)
+		if err != nil {
+			prev = &devdocs.Index{}
+		}
+
+		index, source, opts, err := fetchDoc(cmd.Context(), client, doc, bar)
+		if err != nil {
+			bar.Done("failed")
+			upgradeErrors = append(upgradeErrors, fmt.Sprintf("failed to fetch %s: %v", slug, err))
+			continue
+		}
+		if _, err := store.InstallFrom(slug, index, source, manifest, opts...); err != nil {
+			bar.Done("failed")
+			upgradeErrors = append(upgradeErrors, fmt.Sprintf("failed to upgrade %s: %v", slug, err))
+			continue
+		}
+		bar.Done("upgraded")
+
+		// Partial installs only keep some entries, so diff what was installed
+		if next, err := store.LoadIndex(slug); err == nil {
+			index = next
+		}
+		digests = append(digests, docDigest{
+			Slug:    slug,
+			Name:    doc.Name,
+			Release: doc.Release,
+			Diff:    devdocs.DiffIndex(prev, index),
+		})
+	}
+
+	if len(digests) > 0 {
+		fmt.Println()
+		writeDigest(os.Stdout, digests, digestPreview)
+	}
+
+	if digestFile != "" && len(digests) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# What's new (%s)\n\n", time.Now().Format("2006-01-02"))
+		writeDigest(&b, digests, 0)
+		if err := os.WriteFile(digestFile, []byte(b.String()), 0644); err != nil {
+			return fmt.Errorf("failed to save digest: %w", err)
+		}
+		fmt.Printf("\nDigest saved to %s\n", digestFile)
+	}
+
+	// Report results
+	if len(upgradeErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d upgrade(s) failed:\n", len(upgradeErrors))
+		for _, errMsg := range upgradeErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("%d upgrade(s) failed (see above)", len(upgradeErrors))
+	}
+
+	return nil
+}
+
+// writeDigest writes a markdown digest of upgraded docs. When preview is
+// positive, at most that many entries are listed per section.
+func writeDigest(w io.Writer, digests []docDigest, preview int) {
+	for _, d := range digests {
+		fmt.Fprintf(w, "## %s %s\n\n", d.Name, d.Release)
+		if d.Diff.Empty() {
+			fmt.Fprintf(w, "No entries added or removed.\n\n")
+			continue
+		}
+		writeDigestSection(w, fmt.Sprintf("Added (%d)", len(d.Diff.Added)), d.Diff.Added, preview)
+		writeDigestSection(w, fmt.Sprintf("Removed (%d)", len(d.Diff.Removed)), d.Diff.Removed, preview)
+	}
+}
+
+func writeDigestSection(w io.Writer, title string, entries []devdocs.Entry, preview int) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%s:\n", title)
+	shown := entries
+	if preview > 0 && len(shown) > preview {
+		shown = shown[:preview]
+	}
+	for _, e := range shown {
+		fmt.Fprintf(w, "- %s [%s]\n", e.Name, e.Type)
+	}
+	if len(shown) < len(entries) {
+		fmt.Fprintf(w, "- ... and %d more\n", len(entries)-len(shown))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icampana/dsearch/internal/config"
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+// verifyPreview is how many broken pages are listed per doc.
+const verifyPreview = 10
+
+var verifyRepair bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [doc]...",
+	Short: "Check installed documentation for missing or corrupted files",
+	Long: `Checks that every page of the installed docs is present and matches the
+checksum recorded when it was installed. Without arguments, all installed docs
+are checked.
+
+With --repair, the content of broken docs is downloaded again and only the
+broken pages are rewritten.
+
+Examples:
+  dsearch verify
+  dsearch verify react --repair`,
+	RunE:              runVerify,
+	ValidArgsFunction: completeInstalledDocs,
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyRepair, "repair", false, "re-fetch and rewrite broken pages")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	cfg := config.DefaultPaths()
+	store := devdocs.NewStore(devdocs.WithDataDir(cfg.DataDir), devdocs.WithCacheDir(cfg.CacheDir), devdocs.WithPassphrase(os.Getenv(passphraseEnv)))
+
+	slugs := make([]string, 0, len(args))
+	for _, input := range args {
+		slug, err := resolveInstalledSlug(store, input)
+		if err != nil {
+			return err
+		}
+		slugs = append(slugs, slug)
+	}
+	if len(slugs) == 0 {
+		slugs = store.ListInstalled()
+	}
+	if len(slugs) == 0 {
+		fmt.Println("No documentation installed.")
+		return nil
+	}
+
+	var verifyErrors []string
+	var broken []*devdocs.VerifyResult
+
+	for _, slug := range slugs {
+		if !store.IsInstalled(slug) {
+			verifyErrors = append(verifyErrors, fmt.Sprintf("doc '%s' is not installed", slug))
+			continue
+		}
+		result, err := store.Verify(slug)
+		if err != nil {
+			verifyErrors = append(verifyErrors, fmt.Sprintf("failed to verify %s: %v (reinstall it with: dsearch install %s)", slug, err, slug))
+			continue
+		}
+
+		note := ""
+		if result.Unverified {
+			note = ", no checksums recorded"
+		}
+		if result.OK() {
+			fmt.Printf("%s: OK (%d pages%s)\n", slug, result.Pages, note)
+			continue
+		}
+
+		fmt.Printf("%s: %d missing, %d corrupted of %d pages%s\n", slug, len(result.Missing), len(result.Corrupted), result.Pages, note)
+		for i, page := range result.Broken() {
+			if i == verifyPreview {
+				fmt.Printf("  ... and %d more\n", len(result.Broken())-verifyPreview)
+				break
+			}
+			fmt.Printf("  - %s\n", page)
+		}
+		broken = append(broken, result)
+	}
+
+	if len(broken) > 0 && !verifyRepair {
+		fmt.Println("\nRepair them with: dsearch verify --repair")
+		verifyErrors = append(verifyErrors, fmt.Sprintf("%d doc(s) have broken pages", len(broken)))
+	}
+
+	if len(broken) > 0 && verifyRepair {
+		fmt.Println()
+		verifyErrors = append(verifyErrors, repairDocs(cmd, store, broken)...)
+	}
+
+	if len(verifyErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d problem(s) found:\n", len(verifyErrors))
+		for _, errMsg := range verifyErrors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", errMsg)
+		}
+		return fmt.Errorf("verification failed (see above)")
+	}
+	return nil
+}
+
+// repairDocs re-fetches the content of broken docs and rewrites only their
+// broken pages. It returns the errors of docs that could not be repaired.
+func repairDocs(cmd *cobra.Command, store *devdocs.Store, broken []*devdocs.VerifyResult) []string {
+	client := newClient()
+
+	manifest, err := loadManifest(cmd.Context(), client, store, false)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	docsBySlug := make(map[string]*devdocs.Doc, len(manifest))
+	for i := range manifest {
+		docsBySlug[manifest[i].Slug] = &manifest[i]
+	}
+
+	var repairErrors []string
+	progress := newMultiProgress(os.Stdout)
+	for _, result := range broken {
+		doc, ok := docsBySlug[result.Slug]
+		if !ok {
+			repairErrors = append(repairErrors, fmt.Sprintf("doc '%s' not found in DevDocs catalog", result.Slug))
+			continue
+		}
+		if meta, err := store.LoadMeta(result.Slug); err == nil && meta.Mtime != doc.Mtime {
+			fmt.Fprintf(os.Stderr, "%s %s has a newer release; consider running: dsearch upgrade %s\n", warningPrefix(), result.Slug, result.Slug)
+		}
+
+		bar := progress.Add(fmt.Sprintf("%s %s", doc.Name, doc.Release))
+		_, source, _, err := fetchDoc(cmd.Context(), client, doc, bar)
+		if err != nil {
+			bar.Done("failed")
+			repairErrors = append(repairErrors, fmt.Sprintf("failed to fetch %s: %v", result.Slug, err))
+			continue
+		}
+		repaired, err := store.Repair(result.Slug, result.Broken(), source)
+		if err != nil {
+			bar.Done("failed")
+			repairErrors = append(repairErrors, fmt.Sprintf("failed to repair %s: %v", result.Slug, err))
+			continue
+		}
+		bar.Done(fmt.Sprintf("repaired %d page(s)", repaired))
+	}
+	return repairErrors
+}
@@ -0,0 +1,255 @@
+// Package config provides XDG-compliant configuration and path management.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds user defaults for search flags, loaded from config.yaml.
+// Command-line flags take precedence over environment variables, which take
+// precedence over values from the file.
+type Config struct {
+	Format      string   `yaml:"format"`      // Output format: text, md
+	Limit       int      `yaml:"limit"`       // Maximum number of results
+	Docs        []string `yaml:"docs"`        // Default doc filter
+	Full        bool     `yaml:"full"`        // Show full content without truncation
+	MaxLength   int      `yaml:"max_length"`  // Truncation length when not full
+	Attribution bool     `yaml:"attribution"` // Append the doc's copyright and license notice to rendered content
+	Color       string   `yaml:"color"`       // Color mode: auto, always, never
+	LowMemory   bool     `yaml:"low_memory"`  // Load indices lazily, one doc at a time
+	Compress    bool     `yaml:"compress"`    // Store content of new installs zstd-compressed
+	MaxSize     string   `yaml:"max_size"`    // Disk quota for installed docs, e.g. "2GB" (empty for none)
+	AutoPrune   bool     `yaml:"auto_prune"`  // Remove least recently used docs when an install exceeds max_size
+
+	AutoInstall []string `yaml:"auto_install"` // Docs installed on first use in non-interactive contexts
+	BundleURL   string   `yaml:"bundle_url"`   // Base URL of prebuilt doc bundles
+	UpdateCheck bool     `yaml:"update_check"` // Print a notice when installed docs are outdated
+
+	// Install-time post-processing of content pages, by doc slug or name
+	PostProcess   map[string][]string `yaml:"post_process"`   // Steps: strip-styles, minify, markdown
+	KeepOriginals bool                `yaml:"keep_originals"` // Keep the original HTML of processed pages
+
+	// DevDocs client settings, e.g. for corporate mirrors or self-hosted DevDocs
+	ManifestURL string        `yaml:"manifest_url"` // Base URL serving docs.json
+	ContentURL  string        `yaml:"content_url"`  // Base URL serving <slug>/index.json and db.json
+	Timeout     time.Duration `yaml:"timeout"`      // HTTP request timeout (0 uses the client default)
+	UserAgent   string        `yaml:"user_agent"`   // User-Agent header sent to DevDocs
+	ManifestTTL time.Duration `yaml:"manifest_ttl"` // How long the cached catalog is used before revalidating (0 uses the default)
+}
+
+// Default returns the built-in defaults used when no config file is present.
+func Default() Config {
+	return Config{
+		Format:    "text",
+		Limit:     10,
+		MaxLength: 2000,
+		Color:     "auto",
+	}
+}
+
+// Load reads the config file at path, then applies DSEARCH_* environment overrides.
+// A missing file is only an error when required is true (e.g. set via --config).
+func Load(path string, required bool) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parsing config %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !required:
+		// No config file, use defaults
+	default:
+		return cfg, fmt.Errorf("reading config: %w", err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+	cfg.BundleURL = strings.TrimSuffix(cfg.BundleURL, "/")
+	cfg.ManifestURL = strings.TrimSuffix(cfg.ManifestURL, "/")
+	cfg.ContentURL = strings.TrimSuffix(cfg.ContentURL, "/")
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnv overrides config values with DSEARCH_* environment variables.
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("DSEARCH_FORMAT"); v != "" {
+		c.Format = v
+	}
+	if v := os.Getenv("DSEARCH_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_LIMIT %q: %w", v, err)
+		}
+		c.Limit = n
+	}
+	if v := os.Getenv("DSEARCH_DOCS"); v != "" {
+		c.Docs = splitList(v)
+	}
+	if v := os.Getenv("DSEARCH_AUTO_INSTALL"); v != "" {
+		c.AutoInstall = splitList(v)
+	}
+	if v := os.Getenv("DSEARCH_BUNDLE_URL"); v != "" {
+		c.BundleURL = v
+	}
+	if v := os.Getenv("DSEARCH_MANIFEST_URL"); v != "" {
+		c.ManifestURL = v
+	}
+	if v := os.Getenv("DSEARCH_CONTENT_URL"); v != "" {
+		c.ContentURL = v
+	}
+	if v := os.Getenv("DSEARCH_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_TIMEOUT %q: %w", v, err)
+		}
+		c.Timeout = d
+	}
+	if v := os.Getenv("DSEARCH_MANIFEST_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_MANIFEST_TTL %q: %w", v, err)
+		}
+		c.ManifestTTL = d
+	}
+	if v := os.Getenv("DSEARCH_USER_AGENT"); v != "" {
+		c.UserAgent = v
+	}
+	if v := os.Getenv("DSEARCH_FULL"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_FULL %q: %w", v, err)
+		}
+		c.Full = b
+	}
+	if v := os.Getenv("DSEARCH_MAX_LENGTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_MAX_LENGTH %q: %w", v, err)
+		}
+		c.MaxLength = n
+	}
+	if v := os.Getenv("DSEARCH_ATTRIBUTION"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_ATTRIBUTION %q: %w", v, err)
+		}
+		c.Attribution = b
+	}
+	if v := os.Getenv("DSEARCH_LOW_MEMORY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_LOW_MEMORY %q: %w", v, err)
+		}
+		c.LowMemory = b
+	}
+	if v := os.Getenv("DSEARCH_MAX_SIZE"); v != "" {
+		c.MaxSize = v
+	}
+	if v := os.Getenv("DSEARCH_AUTO_PRUNE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_AUTO_PRUNE %q: %w", v, err)
+		}
+		c.AutoPrune = b
+	}
+	if v := os.Getenv("DSEARCH_COMPRESS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_COMPRESS %q: %w", v, err)
+		}
+		c.Compress = b
+	}
+	if v := os.Getenv("DSEARCH_KEEP_ORIGINALS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_KEEP_ORIGINALS %q: %w", v, err)
+		}
+		c.KeepOriginals = b
+	}
+	if v := os.Getenv("DSEARCH_UPDATE_CHECK"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid DSEARCH_UPDATE_CHECK %q: %w", v, err)
+		}
+		c.UpdateCheck = b
+	}
+	// NO_COLOR (https://no-color.org) disables color unless DSEARCH_COLOR says otherwise
+	if os.Getenv("NO_COLOR") != "" {
+		c.Color = "never"
+	}
+	if v := os.Getenv("DSEARCH_COLOR"); v != "" {
+		c.Color = v
+	}
+	return nil
+}
+
+// Validate checks that config values are usable.
+func (c Config) Validate() error {
+	switch c.Format {
+	case "text", "md":
+	default:
+		return fmt.Errorf("invalid format %q (want text or md)", c.Format)
+	}
+	switch c.Color {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("invalid color mode %q (want auto, always or never)", c.Color)
+	}
+	if c.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d (must be positive)", c.Limit)
+	}
+	if c.MaxLength <= 0 {
+		return fmt.Errorf("invalid max_length %d (must be positive)", c.MaxLength)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %s (must not be negative)", c.Timeout)
+	}
+	if c.MaxSize != "" {
+		if _, err := ParseSize(c.MaxSize); err != nil {
+			return fmt.Errorf("invalid max_size: %w", err)
+		}
+	}
+	for doc, steps := range c.PostProcess {
+		for i, step := range steps {
+			switch step {
+			case "strip-styles", "minify":
+			case "markdown":
+				if i != len(steps)-1 {
+					return fmt.Errorf("invalid post_process for %s: markdown must be the last step", doc)
+				}
+			default:
+				return fmt.Errorf("invalid post_process step %q for %s (want strip-styles, minify or markdown)", step, doc)
+			}
+		}
+	}
+	if c.ManifestTTL < 0 {
+		return fmt.Errorf("invalid manifest_ttl %s (must not be negative)", c.ManifestTTL)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
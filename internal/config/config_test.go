@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		env      map[string]string
+		required bool
+		missing  bool
+		want     Config
+		wantErr  bool
+	}{
+		{
+			name:    "Missing file uses defaults",
+			missing: true,
+			want:    Default(),
+		},
+		{
+			name:     "Missing file with --config is an error",
+			missing:  true,
+			required: true,
+			wantErr:  true,
+		},
+		{
+			name: "Values from file",
+			content: `format: md
+limit: 25
+docs: [react, go]
+full: true
+max_length: 5000
+attribution: true
+color: never
+low_memory: true
+compress: true
+max_size: 2GB
+auto_prune: true
+auto_install: [go]
+bundle_url: https://bundles.example.com/dsearch/
+update_check: true
+post_process:
+  react: [strip-styles, markdown]
+keep_originals: true
+manifest_url: https://mirror.example.com/
+content_url: https://mirror.example.com/docs
+timeout: 90s
+user_agent: acme-dsearch
+manifest_ttl: 1h
+`,
+			want: Config{Format: "md", Limit: 25, Docs: []string{"react", "go"}, Full: true, MaxLength: 5000, Attribution: true, Color: "never", LowMemory: true, Compress: true, MaxSize: "2GB", AutoPrune: true,
+				AutoInstall: []string{"go"}, BundleURL: "https://bundles.example.com/dsearch", UpdateCheck: true,
+				PostProcess: map[string][]string{"react": {"strip-styles", "markdown"}}, KeepOriginals: true,
+				ManifestURL: "https://mirror.example.com", ContentURL: "https://mirror.example.com/docs", Timeout: 90 * time.Second, UserAgent: "acme-dsearch",
+				ManifestTTL: time.Hour},
+		},
+		{
+			name:    "Partial file keeps defaults",
+			content: "limit: 3\n",
+			want:    Config{Format: "text", Limit: 3, MaxLength: 2000, Color: "auto"},
+		},
+		{
+			name:    "Env overrides file",
+			content: "format: text\nlimit: 3\n",
+			env: map[string]string{
+				"DSEARCH_FORMAT":       "md",
+				"DSEARCH_LIMIT":        "7",
+				"DSEARCH_DOCS":         "python~3.12, go",
+				"DSEARCH_FULL":         "true",
+				"DSEARCH_ATTRIBUTION":  "1",
+				"DSEARCH_LOW_MEMORY":   "1",
+				"DSEARCH_AUTO_INSTALL": "go,python~3.12",
+				"DSEARCH_UPDATE_CHECK": "true",
+				"DSEARCH_CONTENT_URL":  "http://devdocs.internal:9292/",
+				"DSEARCH_TIMEOUT":      "5m",
+			},
+			want: Config{Format: "md", Limit: 7, Docs: []string{"python~3.12", "go"}, Full: true, MaxLength: 2000, Attribution: true, Color: "auto", LowMemory: true,
+				AutoInstall: []string{"go", "python~3.12"}, UpdateCheck: true,
+				ContentURL: "http://devdocs.internal:9292", Timeout: 5 * time.Minute},
+		},
+		{
+			name: "NO_COLOR disables color",
+			env:  map[string]string{"NO_COLOR": "1"},
+			want: Config{Format: "text", Limit: 10, MaxLength: 2000, Color: "never"},
+		},
+		{
+			name:    "Invalid format",
+			content: "format: html\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid limit",
+			content: "limit: 0\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid env limit",
+			env:     map[string]string{"DSEARCH_LIMIT": "many"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid env timeout",
+			env:     map[string]string{"DSEARCH_TIMEOUT": "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid max size",
+			env:     map[string]string{"DSEARCH_MAX_SIZE": "big"},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown post-processing step",
+			content: "post_process:\n  go: [gzip]\n",
+			wantErr: true,
+		},
+		{
+			name:    "Markdown before other steps",
+			content: "post_process:\n  go: [markdown, minify]\n",
+			wantErr: true,
+		},
+		{
+			name:    "Negative manifest TTL",
+			content: "manifest_ttl: -1h\n",
+			wantErr: true,
+		},
+		{
+			name:    "Malformed YAML",
+			content: "format: [md\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"DSEARCH_FORMAT", "DSEARCH_LIMIT", "DSEARCH_DOCS", "DSEARCH_FULL",
+				"DSEARCH_MAX_LENGTH", "DSEARCH_ATTRIBUTION", "DSEARCH_COLOR", "DSEARCH_LOW_MEMORY", "DSEARCH_AUTO_INSTALL", "DSEARCH_UPDATE_CHECK", "DSEARCH_KEEP_ORIGINALS", "DSEARCH_COMPRESS", "DSEARCH_MAX_SIZE", "DSEARCH_AUTO_PRUNE",
+				"DSEARCH_MANIFEST_URL", "DSEARCH_CONTENT_URL", "DSEARCH_TIMEOUT", "DSEARCH_USER_AGENT", "DSEARCH_MANIFEST_TTL", "NO_COLOR"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if !tt.missing {
+				path = writeConfig(t, tt.content)
+			}
+
+			got, err := Load(path, tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
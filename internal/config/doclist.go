@@ -0,0 +1,76 @@
+// Package config provides XDG-compliant configuration and path management.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// docListFile is the YAML form of a doc list file.
+type docListFile struct {
+	Docs []string `yaml:"docs"`
+}
+
+// LoadDocList reads a list of docs to install (e.g. "go", "react@18") from a file.
+// Files ending in .yaml/.yml hold either a list or a "docs:" key; any other file
+// is plain text with one doc per line, where blank lines and # comments are ignored.
+func LoadDocList(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading doc list: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseDocListYAML(data)
+	default:
+		return parseDocListText(string(data)), nil
+	}
+}
+
+func parseDocListYAML(data []byte) ([]string, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("parsing doc list: %w", err)
+	}
+	if len(node.Content) == 0 {
+		return nil, nil
+	}
+
+	var docs []string
+	if node.Content[0].Kind == yaml.SequenceNode {
+		if err := node.Decode(&docs); err != nil {
+			return nil, fmt.Errorf("parsing doc list: %w", err)
+		}
+	} else {
+		var file docListFile
+		if err := node.Decode(&file); err != nil {
+			return nil, fmt.Errorf("parsing doc list: %w", err)
+		}
+		docs = file.Docs
+	}
+
+	var cleaned []string
+	for _, doc := range docs {
+		if doc = strings.TrimSpace(doc); doc != "" {
+			cleaned = append(cleaned, doc)
+		}
+	}
+	return cleaned, nil
+}
+
+func parseDocListText(data string) []string {
+	var docs []string
+	for _, line := range strings.Split(data, "\n") {
+		// Strip trailing comments
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		docs = append(docs, strings.Fields(line)...)
+	}
+	return docs
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadDocList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "Text file with comments",
+			filename: "docs.txt",
+			content:  "# Team docs\ngo\n\nreact@18   # frontend\npython~3.12\n",
+			want:     []string{"go", "react@18", "python~3.12"},
+		},
+		{
+			name:     "YAML list",
+			filename: "docs.yaml",
+			content:  "- go\n- react@18\n",
+			want:     []string{"go", "react@18"},
+		},
+		{
+			name:     "YAML docs key",
+			filename: "docs.yml",
+			content:  "docs:\n  - go\n  - python@3.12\n",
+			want:     []string{"go", "python@3.12"},
+		},
+		{
+			name:     "Empty YAML",
+			filename: "docs.yaml",
+			content:  "",
+			want:     nil,
+		},
+		{
+			name:     "Invalid YAML",
+			filename: "docs.yaml",
+			content:  "docs: [go\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadDocList(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadDocList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadDocList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Missing file", func(t *testing.T) {
+		t.Parallel()
+		if _, err := LoadDocList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+			t.Error("Expected error for missing file, got nil")
+		}
+	})
+}
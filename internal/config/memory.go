@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LowMemoryThreshold is the memory limit below which low-memory mode is enabled automatically.
+const LowMemoryThreshold = 256 << 20
+
+// cgroupLimitFiles lists where the container memory limit can be read from
+// (cgroup v2 first, then v1).
+var cgroupLimitFiles = []string{
+	"/sys/fs/cgroup/memory.max",
+	"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+}
+
+// MemoryLimit returns the cgroup memory limit in bytes, or 0 if there is none
+// (or it cannot be determined, e.g. outside Linux).
+func MemoryLimit() int64 {
+	for _, file := range cgroupLimitFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		return parseMemoryLimit(string(data))
+	}
+	return 0
+}
+
+// parseMemoryLimit parses a cgroup memory limit value. "max" and the huge
+// values cgroup v1 uses for "unlimited" yield 0.
+func parseMemoryLimit(s string) int64 {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "max" {
+		return 0
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 || n >= 1<<62 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestParseMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{in: "268435456\n", want: 256 << 20},
+		{in: "max\n", want: 0},
+		{in: "9223372036854771712\n", want: 0}, // cgroup v1 "unlimited"
+		{in: "", want: 0},
+		{in: "garbage", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseMemoryLimit(tt.in); got != tt.want {
+			t.Errorf("parseMemoryLimit(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
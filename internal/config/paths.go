@@ -43,6 +43,11 @@ func DefaultPaths() Paths {
 	}
 }
 
+// ConfigFile returns the path of the default config file.
+func (p Paths) ConfigFile() string {
+	return filepath.Join(p.ConfigDir, "config.yaml")
+}
+
 // EnsureDirs creates all necessary directories if they don't exist.
 func (p Paths) EnsureDirs() error {
 	for _, dir := range []string{p.DataDir, p.CacheDir, p.ConfigDir} {
@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "1024", want: 1024},
+		{in: "500MB", want: 500 * 1000 * 1000},
+		{in: "2GiB", want: 2 << 30},
+		{in: "1.5g", want: 3 << 29},
+		{in: " 10 kb ", want: 10 * 1000},
+		{in: "", wantErr: true},
+		{in: "lots", wantErr: true},
+		{in: "5 parsecs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package devdocs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AmbiguousAliasError is returned when an alias names docs of several families.
+type AmbiguousAliasError struct {
+	Alias string
+	Slugs []string
+}
+
+func (e *AmbiguousAliasError) Error() string {
+	return fmt.Sprintf("alias '%s' is ambiguous, it could mean %s", e.Alias, strings.Join(e.Slugs, ", "))
+}
+
+// ResolveSlug returns the slug input refers to among docs. Slugs win over
+// aliases; an alias (e.g. "ng", or "ng~18" for a release) names the first
+// matching doc of its family, which is the latest release in manifest order.
+// Inputs that are neither are returned unchanged.
+func ResolveSlug(docs []Doc, input string) (string, error) {
+	for _, doc := range docs {
+		if doc.Slug == input {
+			return input, nil
+		}
+	}
+
+	alias, version, _ := strings.Cut(input, "~")
+	var slugs []string
+	families := make(map[string]bool)
+	for _, doc := range docs {
+		if doc.Alias == "" || !strings.EqualFold(doc.Alias, alias) {
+			continue
+		}
+		family, _, _ := strings.Cut(doc.Slug, "~")
+		if families[family] {
+			continue
+		}
+		slug := doc.Slug
+		if version != "" {
+			slug = family + "~" + version
+			if !hasSlug(docs, slug) {
+				continue
+			}
+		}
+		families[family] = true
+		slugs = append(slugs, slug)
+	}
+
+	switch len(slugs) {
+	case 0:
+		return input, nil
+	case 1:
+		return slugs[0], nil
+	}
+	return "", &AmbiguousAliasError{Alias: input, Slugs: slugs}
+}
+
+func hasSlug(docs []Doc, slug string) bool {
+	for _, doc := range docs {
+		if doc.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveInstalled resolves input to an installed doc's slug, like
+// ResolveSlug. Aliases are looked up in the cached manifest, so they only
+// resolve once the catalog has been fetched.
+func (s *Store) ResolveInstalled(input string) (string, error) {
+	if s.IsInstalled(input) {
+		return input, nil
+	}
+	manifest, err := s.LoadManifest()
+	if err != nil {
+		return input, nil
+	}
+
+	var installed []Doc
+	for _, doc := range manifest {
+		if s.IsInstalled(doc.Slug) {
+			installed = append(installed, doc)
+		}
+	}
+	return ResolveSlug(installed, input)
+}
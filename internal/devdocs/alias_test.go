@@ -0,0 +1,83 @@
+package devdocs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveSlug(t *testing.T) {
+	t.Parallel()
+
+	docs := []Doc{
+		{Slug: "angular", Alias: "ng"},
+		{Slug: "angular~18", Alias: "ng"},
+		{Slug: "python~3.12", Alias: "py"},
+		{Slug: "python~3.11", Alias: "py"},
+		{Slug: "pygame", Alias: "py"},
+		{Slug: "js", Alias: "javascript"},
+		{Slug: "javascript", Alias: "js"},
+	}
+
+	tests := []struct {
+		input     string
+		want      string
+		ambiguous bool
+	}{
+		{input: "angular", want: "angular"},
+		{input: "ng", want: "angular"},
+		{input: "NG", want: "angular"},
+		{input: "ng~18", want: "angular~18"},
+		{input: "ng~99", want: "ng~99"},
+		{input: "py", ambiguous: true},
+		{input: "js", want: "js"}, // Slugs win over aliases
+		{input: "react", want: "react"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ResolveSlug(docs, tt.input)
+			var ambiguous *AmbiguousAliasError
+			if tt.ambiguous {
+				if !errors.As(err, &ambiguous) {
+					t.Fatalf("ResolveSlug(%q) error = %v, want an AmbiguousAliasError", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveSlug(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveSlug(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveInstalled(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()), WithCacheDir(t.TempDir()))
+	manifest := []Doc{{Slug: "angular", Alias: "ng"}, {Slug: "python~3.12", Alias: "py"}, {Slug: "pygame", Alias: "py"}}
+	if _, err := store.Install("python~3.12", &Index{}, nil, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a cached manifest, aliases are not known
+	if got, _ := store.ResolveInstalled("py"); got != "py" {
+		t.Errorf("ResolveInstalled(py) without manifest = %q, want py", got)
+	}
+
+	if err := store.SaveManifest(manifest); err != nil {
+		t.Fatal(err)
+	}
+	// Only installed docs are candidates, so py is not ambiguous
+	got, err := store.ResolveInstalled("py")
+	if err != nil || got != "python~3.12" {
+		t.Errorf("ResolveInstalled(py) = %q, %v, want python~3.12", got, err)
+	}
+	if got, _ := store.ResolveInstalled("ng"); got != "ng" {
+		t.Errorf("ResolveInstalled(ng) = %q, want ng (angular is not installed)", got)
+	}
+}
@@ -0,0 +1,211 @@
+// Package devdocs provides types and client for interacting with the DevDocs API
+package devdocs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	// ErrBundleNotFound is returned when no prebuilt bundle is published for a doc.
+	ErrBundleNotFound = errors.New("prebuilt bundle not found")
+	// ErrBundleEncrypted is returned when bundling an encrypted doc without decrypt.
+	ErrBundleEncrypted = errors.New("doc is encrypted at rest and bundles are not")
+)
+
+// Bundle is a prebuilt doc: the DevDocs index and content in one compressed
+// archive (<slug>.tar.gz holding bundle.json, index.json and db.json).
+type Bundle struct {
+	Slug  string `json:"slug"`
+	Mtime int64  `json:"mtime"` // DevDocs mtime the bundle was built from
+
+	Only    []string `json:"only,omitempty"`    // Partial bundle: entry type or path prefixes kept
+	Process []string `json:"process,omitempty"` // Post-processing steps already applied to the content
+
+	Index *Index            `json:"-"`
+	DB    map[string]string `json:"-"`
+}
+
+// WithBundleURL sets the base URL of prebuilt bundles ({url}/{slug}.tar.gz)
+func WithBundleURL(url string) ClientOption {
+	return func(c *Client) {
+		c.bundleURL = url
+	}
+}
+
+// WithBundle installs the content of a bundle, recording it as the source
+// along with the filters and post-processing it was built with, so the install
+// isn't mistaken for a full, unprocessed doc.
+func WithBundle(bundle *Bundle) InstallOption {
+	return func(c *installConfig) {
+		c.source = SourceBundle
+		c.only = bundle.Only
+		c.process = bundle.Process
+	}
+}
+
+// HasBundles reports whether a bundle URL is configured.
+func (c *Client) HasBundles() bool {
+	return c.bundleURL != ""
+}
+
+// FetchBundle downloads and decodes the prebuilt bundle for a doc. progress,
+// if not nil, is called as the archive is read.
+// Returns ErrBundleNotFound when the bundle server has no bundle for it.
+func (c *Client) FetchBundle(ctx context.Context, slug string, progress ProgressFunc) (*Bundle, error) {
+	if c.bundleURL == "" {
+		return nil, ErrBundleNotFound
+	}
+
+	url := fmt.Sprintf("%s/%s.tar.gz", c.bundleURL, slug)
+
+	var bundle *Bundle
+	err := c.retry(ctx, func() error {
+		resp, err := c.get(ctx, url, nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		var body io.Reader = resp.Body
+		if progress != nil {
+			body = &progressReader{r: resp.Body, phase: PhaseDownload, total: resp.ContentLength, progress: progress}
+		}
+		if bundle, err = ReadBundle(body); err != nil {
+			return &decodeError{err}
+		}
+		return nil
+	})
+	var se *statusError
+	if errors.As(err, &se) && se.code == http.StatusNotFound {
+		return nil, ErrBundleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bundle for %s: %w", slug, err)
+	}
+	if bundle.Slug != slug {
+		return nil, fmt.Errorf("bundle for %s contains %s", slug, bundle.Slug)
+	}
+	return bundle, nil
+}
+
+// ReadBundle decodes a bundle archive.
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer gz.Close()
+
+	var bundle Bundle
+	var haveInfo bool
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		dec := json.NewDecoder(tr)
+		switch hdr.Name {
+		case "bundle.json":
+			err = dec.Decode(&bundle)
+			haveInfo = true
+		case "index.json":
+			err = dec.Decode(&bundle.Index)
+		case "db.json":
+			err = dec.Decode(&bundle.DB)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bundle %s: %w", hdr.Name, err)
+		}
+	}
+
+	if !haveInfo || bundle.Index == nil || bundle.DB == nil {
+		return nil, errors.New("incomplete bundle: want bundle.json, index.json and db.json")
+	}
+	if err := ValidateSlug(bundle.Slug); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	return &bundle, nil
+}
+
+// WriteBundle encodes a bundle archive.
+func WriteBundle(w io.Writer, bundle *Bundle) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	files := []struct {
+		name string
+		v    any
+	}{
+		{"bundle.json", bundle},
+		{"index.json", bundle.Index},
+		{"db.json", bundle.DB},
+	}
+	for _, f := range files {
+		data, err := json.Marshal(f.v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", f.name, err)
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// Bundle builds a bundle from an installed doc, e.g. to publish it for other
+// machines. Bundles are plaintext, so encrypted docs are refused with
+// ErrBundleEncrypted unless decrypt is set.
+func (s *Store) Bundle(slug string, decrypt bool) (*Bundle, error) {
+	meta, err := s.LoadMeta(slug)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Encryption != nil && !decrypt {
+		return nil, fmt.Errorf("%s: %w", slug, ErrBundleEncrypted)
+	}
+	index, err := s.LoadIndex(slug)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := s.ListPages(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	db := make(map[string]string, len(pages))
+	for _, page := range pages {
+		// Post-processed docs are bundled as DevDocs serves them, when possible
+		content, err := s.LoadHTML(slug, page)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", slug, page, err)
+		}
+		db[page] = content
+	}
+
+	bundle := &Bundle{Slug: slug, Mtime: meta.Mtime, Only: meta.Only, Index: index, DB: db}
+	if !meta.Originals {
+		bundle.Process = meta.Process
+	}
+	return bundle, nil
+}
@@ -0,0 +1,191 @@
+package devdocs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	want := &Bundle{
+		Slug:  "react",
+		Mtime: 1765113200,
+		Index: &Index{Entries: []Entry{{Name: "useState", Path: "usestate", Type: "Hooks"}}},
+		DB:    map[string]string{"usestate": "<h1>useState</h1>"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, want); err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+	got, err := ReadBundle(&buf)
+	if err != nil {
+		t.Fatalf("ReadBundle() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadBundle() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadBundle_InvalidSlug(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, &Bundle{Slug: "../react", Index: &Index{}, DB: map[string]string{}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBundle(&buf); err == nil {
+		t.Error("ReadBundle() expected error for a slug outside the docs directory")
+	}
+}
+
+func TestFetchBundle(t *testing.T) {
+	t.Parallel()
+
+	var archive bytes.Buffer
+	bundle := &Bundle{Slug: "react", Mtime: 42, Index: &Index{}, DB: map[string]string{}}
+	if err := WriteBundle(&archive, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/react.tar.gz", "/vue.tar.gz": // vue serves the react bundle
+			w.Write(archive.Bytes())
+		case "/broken.tar.gz":
+			w.Write([]byte("not a bundle"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	tests := []struct {
+		name    string
+		client  *Client
+		slug    string
+		wantErr error
+		anyErr  bool
+	}{
+		{name: "Bundle available", client: NewClient(WithBundleURL(ts.URL)), slug: "react"},
+		{name: "No bundle for doc", client: NewClient(WithBundleURL(ts.URL)), slug: "go", wantErr: ErrBundleNotFound},
+		{name: "No bundle URL", client: NewClient(), slug: "react", wantErr: ErrBundleNotFound},
+		{name: "Corrupt bundle", client: NewClient(WithBundleURL(ts.URL)), slug: "broken", anyErr: true},
+		{name: "Bundle for another doc", client: NewClient(WithBundleURL(ts.URL)), slug: "vue", anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.client.FetchBundle(context.Background(), tt.slug, nil)
+			switch {
+			case tt.anyErr:
+				if err == nil {
+					t.Fatal("FetchBundle() expected error")
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("FetchBundle() error = %v, want %v", err, tt.wantErr)
+			case err == nil && got.Mtime != 42:
+				t.Errorf("FetchBundle() Mtime = %d, want 42", got.Mtime)
+			}
+		})
+	}
+}
+
+func TestStoreBundle(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	index := &Index{Entries: []Entry{{Name: "useState", Path: "hooks/usestate", Type: "Hooks"}}}
+	db := map[string]string{"hooks/usestate": "<h1>useState</h1>", "index": "<h1>React</h1>"}
+	if _, err := store.Install("react", index, db, []Doc{{Slug: "react", Mtime: 7}}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	bundle, err := store.Bundle("react", false)
+	if err != nil {
+		t.Fatalf("Bundle() error = %v", err)
+	}
+	want := &Bundle{Slug: "react", Mtime: 7, Index: index, DB: db}
+	if !reflect.DeepEqual(bundle, want) {
+		t.Errorf("Bundle() = %+v, want %+v", bundle, want)
+	}
+}
+
+func TestStoreBundle_PartialAndProcessed(t *testing.T) {
+	t.Parallel()
+
+	process := map[string][]string{"react": {StepMinify}}
+	store := NewStore(WithDataDir(t.TempDir()), WithOnly([]string{"Hooks"}), WithPostProcess(process, false))
+	index := &Index{Entries: []Entry{{Name: "useState", Path: "hooks/usestate", Type: "Hooks"}, {Name: "Component", Path: "component", Type: "Classes"}}}
+	db := map[string]string{"hooks/usestate": "<h1>useState</h1>\n\n<p>Hook</p>", "component": "<h1>Component</h1>"}
+	manifest := []Doc{{Slug: "react", Mtime: 7}}
+	if _, err := store.Install("react", index, db, manifest); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	bundle, err := store.Bundle("react", false)
+	if err != nil {
+		t.Fatalf("Bundle() error = %v", err)
+	}
+	if !reflect.DeepEqual(bundle.Only, []string{"Hooks"}) || !reflect.DeepEqual(bundle.Process, []string{StepMinify}) {
+		t.Fatalf("Bundle() Only = %v, Process = %v, want [Hooks] and [%s]", bundle.Only, bundle.Process, StepMinify)
+	}
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, bundle); err != nil {
+		t.Fatal(err)
+	}
+	if bundle, err = ReadBundle(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Importing it on a machine without filters or post-processing keeps both recorded
+	imported := NewStore(WithDataDir(t.TempDir()))
+	meta, err := imported.Install("react", bundle.Index, bundle.DB, manifest, WithBundle(bundle))
+	if err != nil {
+		t.Fatalf("Install() from bundle error = %v", err)
+	}
+	if !reflect.DeepEqual(meta.Only, []string{"Hooks"}) || !reflect.DeepEqual(meta.Process, []string{StepMinify}) {
+		t.Errorf("Install() from bundle Only = %v, Process = %v, want [Hooks] and [%s]", meta.Only, meta.Process, StepMinify)
+	}
+
+	// Steps the bundle already went through aren't recorded twice
+	processing := NewStore(WithDataDir(t.TempDir()), WithPostProcess(map[string][]string{"react": {StepMinify, StepMarkdown}}, true))
+	meta, err = processing.Install("react", bundle.Index, bundle.DB, manifest, WithBundle(bundle))
+	if err != nil {
+		t.Fatalf("Install() from bundle error = %v", err)
+	}
+	if want := []string{StepMinify, StepMarkdown}; !reflect.DeepEqual(meta.Process, want) || meta.Originals {
+		t.Errorf("Install() from bundle Process = %v, Originals = %v, want %v without originals", meta.Process, meta.Originals, want)
+	}
+}
+
+func TestStoreBundle_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()), WithEncryption("secret"))
+	db := map[string]string{"index": "<h1>Internal</h1>"}
+	if _, err := store.Install("internal", &Index{}, db, []Doc{{Slug: "internal", Mtime: 7}}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	// Encrypted docs aren't written to plaintext bundles by accident
+	if _, err := store.Bundle("internal", false); !errors.Is(err, ErrBundleEncrypted) {
+		t.Fatalf("Bundle() error = %v, want %v", err, ErrBundleEncrypted)
+	}
+
+	bundle, err := store.Bundle("internal", true)
+	if err != nil {
+		t.Fatalf("Bundle() with decrypt error = %v", err)
+	}
+	if !reflect.DeepEqual(bundle.DB, db) {
+		t.Errorf("Bundle() with decrypt DB = %v, want %v", bundle.DB, db)
+	}
+}
@@ -2,24 +2,35 @@
 package devdocs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 )
 
 const (
 	defaultTimeout     = 60 * time.Second
+	defaultRetries     = 3
 	defaultManifestURL = "https://devdocs.io"
 	defaultContentURL  = "https://documents.devdocs.io"
+	defaultUserAgent   = "dsearch"
 )
 
 // Client is an HTTP client for fetching DevDocs data
 type Client struct {
 	manifestURL string
 	contentURL  string
+	bundleURL   string // Optional prebuilt bundles, tried before DevDocs
 	httpClient  *http.Client
+	userAgent   string // Sent with every request
+	retries     int    // Extra attempts for failed requests
+	downloadDir string // Where partial db.json downloads are kept for resuming
+
+	timeout *time.Duration // Applied to httpClient once all options have run
 }
 
 // ClientOption allows configuring the client
@@ -47,110 +58,290 @@ func WithContentURL(url string) ClientOption {
 	}
 }
 
-// WithTimeout sets a custom timeout
+// WithTimeout sets a custom timeout, also for a client given with WithHTTPClient
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
-		c.httpClient.Timeout = timeout
+		c.timeout = &timeout
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client (e.g., for proxies or transports).
+// A nil client is ignored.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with requests
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
 	}
 }
 
-// NewClient creates a new DevDocs API client.
+// WithRetries sets how many times a failed request is retried (with exponential backoff)
+func WithRetries(n int) ClientOption {
+	return func(c *Client) {
+		c.retries = n
+	}
+}
+
+// WithDownloadDir keeps db.json downloads in dir so interrupted downloads can be resumed
+func WithDownloadDir(dir string) ClientOption {
+	return func(c *Client) {
+		c.downloadDir = dir
+	}
+}
+
+// NewClient creates a new DevDocs API client. Requests go through the proxy
+// set in HTTPS_PROXY, HTTP_PROXY and NO_PROXY, if any.
 func NewClient(opts ...ClientOption) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
 	c := &Client{
 		manifestURL: defaultManifestURL,
 		contentURL:  defaultContentURL,
 		httpClient: &http.Client{
-			Timeout: defaultTimeout,
+			Timeout:   defaultTimeout,
+			Transport: transport,
 		},
+		userAgent: defaultUserAgent,
+		retries:   defaultRetries,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	// The timeout goes on a copy, so a caller's HTTP client is left as is
+	if c.timeout != nil {
+		httpClient := *c.httpClient
+		httpClient.Timeout = *c.timeout
+		c.httpClient = &httpClient
+	}
+
 	return c
 }
 
 // FetchManifest fetches the docs.json manifest from DevDocs
 // Returns a list of all available documentation
-func (c *Client) FetchManifest() ([]Doc, error) {
-	url := fmt.Sprintf("%s/docs.json", c.manifestURL)
+func (c *Client) FetchManifest(ctx context.Context) ([]Doc, error) {
+	docs, _, err := c.FetchManifestIfChanged(ctx, CacheValidators{})
+	return docs, err
+}
 
-	resp, err := c.httpClient.Get(url)
+// FetchIndex fetches the index.json for a specific documentation slug
+// Returns the search index containing entries and types. progress, if not nil,
+// is called as the body is read.
+func (c *Client) FetchIndex(ctx context.Context, slug string, progress ProgressFunc) (*Index, error) {
+	url := fmt.Sprintf("%s/%s/index.json", c.contentURL, slug)
+
+	body, err := c.fetch(ctx, url, progress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+		return nil, fmt.Errorf("failed to fetch index for %s: %w", slug, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("fetch manifest failed with status %d", resp.StatusCode)
+	var index Index
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal index: %w", err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	return &index, nil
+}
+
+// ContentFunc receives one page of a doc's content (a db.json entry)
+type ContentFunc func(path, content string) error
+
+// Phase is the stage of a fetch that progress is reported for.
+type Phase string
+
+const (
+	PhaseDownload Phase = "downloading" // Bytes received from the server
+	PhaseExtract  Phase = "extracting"  // Bytes of a downloaded file decoded into pages
+)
+
+// ProgressFunc reports progress in bytes. total is -1 when unknown.
+type ProgressFunc func(phase Phase, done, total int64)
+
+// FetchDB fetches the db.json for a specific documentation slug
+// Returns a map of content paths to HTML strings
+func (c *Client) FetchDB(ctx context.Context, slug string) (map[string]string, error) {
+	db := make(map[string]string)
+	err := c.StreamDB(ctx, slug, 0, func(path, content string) error {
+		db[path] = content
+		return nil
+	}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read manifest body: %w", err)
+		return nil, err
 	}
+	return db, nil
+}
+
+// StreamDB downloads the db.json for a doc and passes each page to fn as it
+// is decoded, so memory use stays bounded by the largest page rather than the
+// whole file. progress, if not nil, is called as the body is read.
+//
+// size is the db.json size listed in the catalog (Doc.DBSize), or 0 when
+// unknown. A db.json that fails to decode before reaching that size, or that
+// is less than half of it, is reported as a *TruncatedError.
+//
+// With a download directory, db.json is first saved there (resuming any
+// earlier partial download) and then decoded from disk, which is reported as
+// PhaseExtract.
+func (c *Client) StreamDB(ctx context.Context, slug string, size int64, fn ContentFunc, progress ProgressFunc) error {
+	url := fmt.Sprintf("%s/%s/db.json", c.contentURL, slug)
+
+	if c.downloadDir != "" {
+		path := filepath.Join(c.downloadDir, slug+".db.json")
+		if err := c.download(ctx, url, path, progress); err != nil {
+			return fmt.Errorf("failed to fetch db for %s: %w", slug, err)
+		}
 
-	var docs []Doc
-	if err := json.Unmarshal(body, &docs); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to read db for %s: %w", slug, err)
+		}
+		counter := &countingReader{r: f}
+		var r io.Reader = counter
+		if progress != nil {
+			total := int64(-1)
+			if info, err := f.Stat(); err == nil {
+				total = info.Size()
+			}
+			r = &progressReader{r: counter, phase: PhaseExtract, total: total, progress: progress}
+		}
+		err = checkDBSize(slug, size, counter, DecodeDB(r, fn))
+		f.Close()
+		if err != nil {
+			// The complete download is bad, so the next attempt starts over
+			os.Remove(path)
+			return fmt.Errorf("failed to decode db for %s: %w", slug, err)
+		}
+		return os.Remove(path)
 	}
 
-	return docs, nil
-}
+	err := c.retry(ctx, func() error {
+		resp, err := c.get(ctx, url, nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
 
-// FetchIndex fetches the index.json for a specific documentation slug
-// Returns the search index containing entries and types
-func (c *Client) FetchIndex(slug string) (*Index, error) {
-	url := fmt.Sprintf("%s/%s/index.json", c.contentURL, slug)
+		counter := &countingReader{r: resp.Body}
+		var body io.Reader = counter
+		if progress != nil {
+			body = &progressReader{r: counter, phase: PhaseDownload, total: resp.ContentLength, progress: progress}
+		}
 
-	resp, err := c.httpClient.Get(url)
+		return checkDBSize(slug, size, counter, DecodeDB(body, fn))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch index for %s: %w", slug, err)
+		return fmt.Errorf("failed to fetch db for %s: %w", slug, err)
 	}
-	defer resp.Body.Close()
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("fetch index for %s failed with status %d", slug, resp.StatusCode)
+// DecodeDB decodes a db.json object one entry at a time, calling fn for each page.
+func DecodeDB(r io.Reader, fn ContentFunc) error {
+	dec := json.NewDecoder(r)
+
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("expected object, got %v", tok)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read index body: %w", err)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		path, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected page path, got %v", tok)
+		}
+
+		var content string
+		if err := dec.Decode(&content); err != nil {
+			return fmt.Errorf("page %s: %w", path, err)
+		}
+		if err := fn(path, content); err != nil {
+			return err
+		}
 	}
 
-	var index Index
-	if err := json.Unmarshal(body, &index); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal index: %w", err)
+	if _, err := dec.Token(); err != nil {
+		return err
 	}
+	return nil
+}
 
-	return &index, nil
+// TruncatedError is returned when a downloaded db.json is shorter than the
+// size listed in the catalog. Retrying the download usually fixes it.
+type TruncatedError struct {
+	Slug string
+	Got  int64
+	Want int64
 }
 
-// FetchDB fetches the db.json for a specific documentation slug
-// Returns a map of content paths to HTML strings
-func (c *Client) FetchDB(slug string) (map[string]string, error) {
-	url := fmt.Sprintf("%s/%s/db.json", c.contentURL, slug)
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("db.json is truncated (got %d of %d bytes), try again", e.Got, e.Want)
+}
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch db for %s: %w", slug, err)
+// checkDBSize validates a decoded db.json of n bytes against the catalog
+// size. Errors reading the data (e.g. a dropped connection) are retryable.
+// Decoding errors are reported as truncation when the data stopped short of
+// size, and as non-retryable decode errors otherwise. Complete JSON
+// that is smaller than the catalog size is accepted unless it is obviously
+// truncated (under half the size), since the catalog may be older than the
+// content.
+func checkDBSize(slug string, size int64, counter *countingReader, decodeErr error) error {
+	switch {
+	case decodeErr != nil && counter.err != nil:
+		return fmt.Errorf("failed to read db.json: %w", counter.err)
+	case decodeErr != nil && size > 0 && counter.n < size:
+		return &TruncatedError{Slug: slug, Got: counter.n, Want: size}
+	case decodeErr != nil:
+		return &decodeError{decodeErr}
+	case size > 0 && counter.n < size/2:
+		return &TruncatedError{Slug: slug, Got: counter.n, Want: size}
 	}
-	defer resp.Body.Close()
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("fetch db for %s failed with status %d", slug, resp.StatusCode)
-	}
+// countingReader counts the bytes read through it, and records the first
+// error of the underlying reader other than io.EOF.
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read db body: %w", err)
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+	if err != nil && err != io.EOF && c.err == nil {
+		c.err = err
 	}
+	return n, err
+}
 
-	var db map[string]string
-	if err := json.Unmarshal(body, &db); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal db: %w", err)
-	}
+// progressReader reports the number of bytes read to a ProgressFunc.
+type progressReader struct {
+	r        io.Reader
+	phase    Phase
+	done     int64
+	total    int64
+	progress ProgressFunc
+}
 
-	return db, nil
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	p.progress(p.phase, p.done, p.total)
+	return n, err
 }
@@ -2,12 +2,24 @@
 package devdocs
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
+func init() {
+	// Keep retry backoff short in tests
+	retryBaseDelay = time.Millisecond
+}
+
 func TestFetchManifest(t *testing.T) {
 	// Mock server
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -42,7 +54,7 @@ func TestFetchManifest(t *testing.T) {
 
 	// Test client
 	client := NewClient(WithBaseURL(ts.URL))
-	docs, err := client.FetchManifest()
+	docs, err := client.FetchManifest(context.Background())
 	if err != nil {
 		t.Fatalf("FetchManifest() error = %v", err)
 	}
@@ -79,7 +91,7 @@ func TestFetchIndex(t *testing.T) {
 	defer ts.Close()
 
 	client := NewClient(WithBaseURL(ts.URL))
-	index, err := client.FetchIndex("react")
+	index, err := client.FetchIndex(context.Background(), "react", nil)
 	if err != nil {
 		t.Fatalf("FetchIndex() error = %v", err)
 	}
@@ -110,7 +122,7 @@ func TestFetchDB(t *testing.T) {
 	defer ts.Close()
 
 	client := NewClient(WithBaseURL(ts.URL))
-	db, err := client.FetchDB("react")
+	db, err := client.FetchDB(context.Background(), "react")
 	if err != nil {
 		t.Fatalf("FetchDB() error = %v", err)
 	}
@@ -128,6 +140,106 @@ func TestFetchDB(t *testing.T) {
 	}
 }
 
+func TestDecodeDB(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name    string
+		input   string
+		fnErr   error
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "Pages in order",
+			input: `{"index": "<h1>Index</h1>", "a/b": "<p>\u003cescaped\u003e</p>"}`,
+			want:  map[string]string{"index": "<h1>Index</h1>", "a/b": "<p><escaped></p>"},
+		},
+		{name: "Empty object", input: `{}`, want: map[string]string{}},
+		{name: "Not an object", input: `["index"]`, wantErr: true},
+		{name: "Non-string content", input: `{"index": 1}`, wantErr: true},
+		{name: "Truncated", input: `{"index": "<h1>`, wantErr: true},
+		{name: "Callback error stops decoding", input: `{"index": "x"}`, fnErr: errStop, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := make(map[string]string)
+			err := DecodeDB(strings.NewReader(tt.input), func(path, content string) error {
+				got[path] = content
+				return tt.fnErr
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.fnErr != nil && !errors.Is(err, tt.fnErr) {
+				t.Errorf("DecodeDB() error = %v, want %v", err, tt.fnErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeDB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamDBProgress(t *testing.T) {
+	t.Parallel()
+
+	body := `{"index": "<h1>React</h1>", "usestate": "<h1>useState</h1>"}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(ts.Close)
+
+	tests := []struct {
+		name       string
+		opts       []ClientOption
+		wantPhases []Phase
+	}{
+		{name: "Streamed", wantPhases: []Phase{PhaseDownload}},
+		{name: "Downloaded then extracted", opts: []ClientOption{WithDownloadDir(t.TempDir())}, wantPhases: []Phase{PhaseDownload, PhaseExtract}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var pages int
+			var phases []Phase
+			last := make(map[Phase][2]int64)
+			client := NewClient(append([]ClientOption{WithBaseURL(ts.URL)}, tt.opts...)...)
+			err := client.StreamDB(context.Background(), "react", 0, func(path, content string) error {
+				pages++
+				return nil
+			}, func(phase Phase, done, total int64) {
+				if len(phases) == 0 || phases[len(phases)-1] != phase {
+					phases = append(phases, phase)
+				}
+				last[phase] = [2]int64{done, total}
+			})
+			if err != nil {
+				t.Fatalf("StreamDB() error = %v", err)
+			}
+
+			if pages != 2 {
+				t.Errorf("StreamDB() streamed %d pages, want 2", pages)
+			}
+			if !reflect.DeepEqual(phases, tt.wantPhases) {
+				t.Errorf("progress phases = %v, want %v", phases, tt.wantPhases)
+			}
+			for _, phase := range tt.wantPhases {
+				if got := last[phase]; got[0] != int64(len(body)) || got[1] != int64(len(body)) {
+					t.Errorf("%s progress = %d/%d, want %d/%d", phase, got[0], got[1], len(body), len(body))
+				}
+			}
+		})
+	}
+}
+
 func TestFetchManifestHTTPError(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -135,7 +247,7 @@ func TestFetchManifestHTTPError(t *testing.T) {
 	defer ts.Close()
 
 	client := NewClient(WithBaseURL(ts.URL))
-	_, err := client.FetchManifest()
+	_, err := client.FetchManifest(context.Background())
 	if err == nil {
 		t.Error("Expected error for 500 response, got nil")
 	}
@@ -149,8 +261,168 @@ func TestFetchIndexInvalidJSON(t *testing.T) {
 	defer ts.Close()
 
 	client := NewClient(WithBaseURL(ts.URL))
-	_, err := client.FetchIndex("react")
+	_, err := client.FetchIndex(context.Background(), "react", nil)
 	if err == nil {
 		t.Error("Expected error for invalid JSON, got nil")
 	}
 }
+
+func TestWithHTTPClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "yes" {
+			t.Errorf("Expected custom transport header, got %q", r.Header.Get("X-Test"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer ts.Close()
+
+	httpClient := &http.Client{Transport: headerTransport{"X-Test", "yes"}}
+	client := NewClient(WithBaseURL(ts.URL), WithHTTPClient(httpClient))
+	if _, err := client.FetchManifest(context.Background()); err != nil {
+		t.Fatalf("FetchManifest() error = %v", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	custom := &http.Client{Transport: headerTransport{"X-Test", "yes"}}
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want time.Duration
+	}{
+		{name: "Default", want: defaultTimeout},
+		{name: "Timeout", opts: []ClientOption{WithTimeout(5 * time.Second)}, want: 5 * time.Second},
+		{name: "Timeout before HTTP client", opts: []ClientOption{WithTimeout(5 * time.Second), WithHTTPClient(custom)}, want: 5 * time.Second},
+		{name: "Timeout after HTTP client", opts: []ClientOption{WithHTTPClient(custom), WithTimeout(5 * time.Second)}, want: 5 * time.Second},
+		{name: "Nil HTTP client is ignored", opts: []ClientOption{WithHTTPClient(nil)}, want: defaultTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := NewClient(tt.opts...)
+			if client.httpClient == nil {
+				t.Fatal("NewClient() has no HTTP client")
+			}
+			if client.httpClient.Timeout != tt.want {
+				t.Errorf("NewClient() timeout = %v, want %v", client.httpClient.Timeout, tt.want)
+			}
+			if custom.Timeout != 0 {
+				t.Errorf("WithTimeout() changed the caller's HTTP client timeout to %v", custom.Timeout)
+			}
+		})
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want string
+	}{
+		{name: "Default", want: "dsearch"},
+		{name: "Custom", opts: []ClientOption{WithUserAgent("dsearch/1.2.3 (mirror)")}, want: "dsearch/1.2.3 (mirror)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("User-Agent"); got != tt.want {
+					t.Errorf("User-Agent = %q, want %q", got, tt.want)
+				}
+				w.Write([]byte("[]"))
+			}))
+			t.Cleanup(ts.Close)
+
+			client := NewClient(append([]ClientOption{WithBaseURL(ts.URL)}, tt.opts...)...)
+			if _, err := client.FetchManifest(context.Background()); err != nil {
+				t.Fatalf("FetchManifest() error = %v", err)
+			}
+		})
+	}
+}
+
+// headerTransport adds a fixed header to every request
+type headerTransport struct {
+	key, value string
+}
+
+func (h headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set(h.key, h.value)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestStreamDBSize(t *testing.T) {
+	t.Parallel()
+
+	body := `{"index": "<h1>React</h1>", "usestate": "<h1>useState</h1>"}`
+	n := int64(len(body))
+
+	tests := []struct {
+		name          string
+		body          string
+		size          int64
+		opts          []ClientOption
+		dropFirst     bool // The first response is cut off by a dropped connection
+		wantTruncated bool
+		wantErr       bool
+		wantRequests  int
+	}{
+		{name: "Exact size", body: body, size: n, wantRequests: 1},
+		{name: "Unknown size", body: body, size: 0, wantRequests: 1},
+		{name: "Catalog older than content", body: body, size: n + 10, wantRequests: 1},
+		{name: "Cut off mid-stream is retried", body: body[:20], size: n, wantTruncated: true, wantRequests: 3},
+		{name: "Complete but obviously short", body: `{}`, size: n, wantTruncated: true, wantRequests: 3},
+		{name: "Dropped connection is retried", body: body, size: 0, dropFirst: true, wantRequests: 2},
+		{name: "Malformed is not retried", body: `{"index": 1}`, size: 0, wantErr: true, wantRequests: 1},
+		{name: "Cut off download", body: body[:20], size: n, opts: []ClientOption{WithDownloadDir(t.TempDir())}, wantTruncated: true, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			requests := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests++
+				first := requests == 1
+				mu.Unlock()
+				if tt.dropFirst && first {
+					// Writing less than the Content-Length closes the connection
+					w.Header().Set("Content-Length", strconv.Itoa(len(tt.body)))
+					w.Write([]byte(tt.body[:20]))
+					return
+				}
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(ts.Close)
+
+			client := NewClient(append([]ClientOption{WithBaseURL(ts.URL), WithRetries(2)}, tt.opts...)...)
+			err := client.StreamDB(context.Background(), "react", tt.size, func(path, content string) error {
+				return nil
+			}, nil)
+
+			var te *TruncatedError
+			if got := errors.As(err, &te); got != tt.wantTruncated {
+				t.Errorf("StreamDB() error = %v, want truncated %v", err, tt.wantTruncated)
+			}
+			if (err != nil) != (tt.wantErr || tt.wantTruncated) {
+				t.Errorf("StreamDB() error = %v, wantErr %v", err, tt.wantErr || tt.wantTruncated)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("StreamDB() made %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package devdocs
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMagic starts every zstd frame, so compressed pages are recognized by content.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// zstd encoders and decoders are safe for concurrent EncodeAll/DecodeAll calls,
+// so one of each is shared by the process.
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil)
+	})
+)
+
+// compress compresses a content page with zstd. Pages too small to benefit
+// are returned unchanged; readers tell both apart by content.
+func compress(data []byte) ([]byte, error) {
+	enc, err := zstdEncoder()
+	if err != nil {
+		return nil, err
+	}
+	if out := enc.EncodeAll(data, nil); len(out) < len(data) {
+		return out, nil
+	}
+	return data, nil
+}
+
+// decompress decompresses a zstd-compressed content page.
+func decompress(data []byte) ([]byte, error) {
+	dec, err := zstdDecoder()
+	if err != nil {
+		return nil, err
+	}
+	out, err := dec.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	return out, nil
+}
+
+// isCompressed reports whether data is a zstd frame.
+func isCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, zstdMagic)
+}
+
+// IsCompressed reports whether an installed doc stores its content compressed.
+func (s *Store) IsCompressed(slug string) bool {
+	meta, err := s.LoadMeta(slug)
+	return err == nil && meta.Compressed
+}
+
+// Compress compresses the content pages of an installed doc in place and
+// returns the content size before and after. It is safe to repeat after an
+// interruption: pages already compressed are skipped.
+func (s *Store) Compress(slug string) (before, after int64, err error) {
+	lock, err := s.lockDoc(slug)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer lock.release()
+
+	meta, err := s.LoadMeta(slug)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var aead cipher.AEAD
+	if meta.Encryption != nil {
+		if aead, err = s.cipherFor(slug); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// Meta is written first so new installs of the doc keep compressing
+	if !meta.Compressed {
+		meta.Compressed = true
+		if err := writeJSON(filepath.Join(s.DocDir(slug), "meta.json"), meta); err != nil {
+			return 0, 0, fmt.Errorf("failed to save meta: %w", err)
+		}
+	}
+
+	pages, err := s.ListPages(slug)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var files []string
+	for _, page := range pages {
+		files = append(files, s.pageFiles(meta, page)...)
+	}
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return before, after, fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		before += info.Size()
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return before, after, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if aead != nil && isEncrypted(data) {
+			if data, err = unseal(aead, data); err != nil {
+				return before, after, fmt.Errorf("failed to decrypt %s: %w", file, err)
+			}
+		}
+		if isCompressed(data) {
+			after += info.Size()
+			continue
+		}
+
+		if data, err = compress(data); err != nil {
+			return before, after, fmt.Errorf("failed to compress %s: %w", file, err)
+		}
+		if err := writeDocFile(file, data, aead); err != nil {
+			return before, after, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		if info, err = os.Stat(file); err == nil {
+			after += info.Size()
+		}
+	}
+
+	return before, after, nil
+}
@@ -0,0 +1,103 @@
+package devdocs
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestInstallCompressed(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	page := "<h1>useState</h1>" + strings.Repeat("<p>Returns a stateful value.</p>", 50)
+	db := map[string]string{"reference/usestate": page}
+	manifest := []Doc{{Slug: "react", Mtime: 1}}
+
+	store := NewStore(WithDataDir(tmpDir), WithCompression(true))
+	meta, err := store.Install("react", &Index{}, db, manifest)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !meta.Compressed {
+		t.Error("Install() meta.Compressed = false, want true")
+	}
+
+	data, err := os.ReadFile(store.ContentPath("react", "reference/usestate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCompressed(data) || len(data) >= len(page) {
+		t.Errorf("content file is %d bytes and compressed=%v, want a smaller zstd frame", len(data), isCompressed(data))
+	}
+
+	// Reinstalling without the option keeps the doc compressed
+	if _, err := NewStore(WithDataDir(tmpDir)).Install("react", &Index{}, db, manifest); err != nil {
+		t.Fatalf("reinstall error = %v", err)
+	}
+
+	content, err := NewStore(WithDataDir(tmpDir)).LoadContent("react", "reference/usestate#state")
+	if err != nil {
+		t.Fatalf("LoadContent() error = %v", err)
+	}
+	if content != page {
+		t.Errorf("LoadContent() = %q, want the original page", content)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	page := strings.Repeat("<p>Package http provides HTTP client and server implementations.</p>", 40)
+	db := map[string]string{"net/http/index": page, "fmt/index": "<h1>fmt</h1>"}
+	manifest := []Doc{{Slug: "go", Mtime: 1}}
+
+	tests := []struct {
+		name string
+		opts []StoreOption
+	}{
+		{name: "Plain doc"},
+		{name: "Encrypted doc", opts: []StoreOption{WithEncryption("hunter2")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewStore(append([]StoreOption{WithDataDir(t.TempDir())}, tt.opts...)...)
+			if _, err := store.Install("go", &Index{}, db, manifest); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			before, after, err := store.Compress("go")
+			if err != nil {
+				t.Fatalf("Compress() error = %v", err)
+			}
+			if after >= before {
+				t.Errorf("Compress() size %d -> %d, want smaller", before, after)
+			}
+			if !store.IsCompressed("go") {
+				t.Error("IsCompressed() = false after Compress()")
+			}
+
+			// Repeating is a no-op
+			again, _, err := store.Compress("go")
+			if err != nil {
+				t.Fatalf("second Compress() error = %v", err)
+			}
+			if again != after {
+				t.Errorf("second Compress() started from %d bytes, want %d", again, after)
+			}
+
+			for path, want := range db {
+				got, err := store.LoadContent("go", path)
+				if err != nil {
+					t.Fatalf("LoadContent(%s) error = %v", path, err)
+				}
+				if got != want {
+					t.Errorf("LoadContent(%s) = %q, want %q", path, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Package devdocs provides types and client for interacting with the DevDocs API
+package devdocs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	// ErrPassphraseRequired is returned when reading or writing an encrypted doc without a passphrase.
+	ErrPassphraseRequired = errors.New("doc is encrypted: passphrase required")
+	// ErrWrongPassphrase is returned when the passphrase does not match an encrypted doc.
+	ErrWrongPassphrase = errors.New("wrong passphrase for encrypted doc")
+)
+
+// encryptedMagic prefixes every encrypted file, followed by the GCM nonce and ciphertext.
+var encryptedMagic = []byte("DSENC1\x00")
+
+// pbkdf2Iterations is the PBKDF2-SHA256 work factor for new encrypted docs.
+var pbkdf2Iterations = 600_000
+
+// checkPlaintext is sealed into the meta to verify the passphrase before decrypting content.
+const checkPlaintext = "dsearch"
+
+// Encryption describes how an encrypted doc's key is derived from the passphrase.
+type Encryption struct {
+	KDF        string `json:"kdf"` // Key derivation function (pbkdf2-sha256)
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Check      []byte `json:"check"` // checkPlaintext sealed with the derived key
+}
+
+// newEncryption creates encryption parameters with a fresh salt and returns the derived cipher.
+func newEncryption(passphrase string) (*Encryption, cipher.AEAD, error) {
+	enc := &Encryption{KDF: "pbkdf2-sha256", Iterations: pbkdf2Iterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := enc.deriveCipher(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	if enc.Check, err = seal(aead, []byte(checkPlaintext)); err != nil {
+		return nil, nil, err
+	}
+	return enc, aead, nil
+}
+
+// open derives the cipher for an existing doc and verifies the passphrase.
+func (enc *Encryption) open(passphrase string) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	if enc.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported key derivation %q", enc.KDF)
+	}
+
+	aead, err := enc.deriveCipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := unseal(aead, enc.Check); err != nil || string(check) != checkPlaintext {
+		return nil, ErrWrongPassphrase
+	}
+	return aead, nil
+}
+
+// deriveCipher derives an AES-256-GCM cipher from the passphrase.
+func (enc *Encryption) deriveCipher(passphrase string) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, enc.Salt, enc.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data into the encrypted file format.
+func seal(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(encryptedMagic)+len(nonce)+len(data)+aead.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, data, nil), nil
+}
+
+// unseal decrypts data in the encrypted file format.
+func unseal(aead cipher.AEAD, data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(data, encryptedMagic)
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("encrypted data too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+// isEncrypted reports whether data is in the encrypted file format.
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// cipherFor returns the (cached) cipher of an encrypted doc.
+func (s *Store) cipherFor(slug string) (cipher.AEAD, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if aead, ok := s.ciphers[slug]; ok {
+		return aead, nil
+	}
+
+	meta, err := s.LoadMeta(slug)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Encryption == nil {
+		return nil, fmt.Errorf("doc %s has encrypted files but no encryption metadata", slug)
+	}
+	aead, err := meta.Encryption.open(s.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", slug, err)
+	}
+
+	if s.ciphers == nil {
+		s.ciphers = make(map[string]cipher.AEAD)
+	}
+	s.ciphers[slug] = aead
+	return aead, nil
+}
+
+// setCipher replaces the cached cipher of a doc after it was (re)installed or removed.
+func (s *Store) setCipher(slug string, aead cipher.AEAD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if aead == nil {
+		delete(s.ciphers, slug)
+		return
+	}
+	if s.ciphers == nil {
+		s.ciphers = make(map[string]cipher.AEAD)
+	}
+	s.ciphers[slug] = aead
+}
+
+// readDocFile reads a file of an installed doc, decrypting and decompressing
+// it if needed.
+func (s *Store) readDocFile(slug, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isEncrypted(data) {
+		aead, err := s.cipherFor(slug)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = unseal(aead, data); err != nil {
+			return nil, err
+		}
+	}
+	if isCompressed(data) {
+		return decompress(data)
+	}
+	return data, nil
+}
+
+// writeDocFile writes a file of a doc, encrypting it when aead is not nil.
+func writeDocFile(path string, data []byte, aead cipher.AEAD) error {
+	if aead != nil {
+		var err error
+		if data, err = seal(aead, data); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(path, data)
+}
+
+// IsEncrypted reports whether an installed doc is encrypted at rest.
+func (s *Store) IsEncrypted(slug string) bool {
+	meta, err := s.LoadMeta(slug)
+	return err == nil && meta.Encryption != nil
+}
+
+// Encrypt encrypts an installed doc in place with the store's passphrase.
+// It is safe to repeat after an interruption: files already encrypted are skipped.
+func (s *Store) Encrypt(slug string) error {
+	if s.passphrase == "" {
+		return ErrPassphraseRequired
+	}
+
+	lock, err := s.lockDoc(slug)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	meta, err := s.LoadMeta(slug)
+	if err != nil {
+		return err
+	}
+
+	var aead cipher.AEAD
+	if meta.Encryption != nil {
+		// Resume with the existing key
+		if aead, err = meta.Encryption.open(s.passphrase); err != nil {
+			return fmt.Errorf("%s: %w", slug, err)
+		}
+	} else {
+		// Meta is written first so files encrypted before an interruption stay readable
+		if meta.Encryption, aead, err = newEncryption(s.passphrase); err != nil {
+			return err
+		}
+		if err := writeJSON(filepath.Join(s.DocDir(slug), "meta.json"), meta); err != nil {
+			return fmt.Errorf("failed to save meta: %w", err)
+		}
+	}
+
+	files := []string{filepath.Join(s.DocDir(slug), "index.json")}
+	checksums := filepath.Join(s.DocDir(slug), checksumsFile)
+	if _, err := os.Stat(checksums); err == nil {
+		files = append(files, checksums)
+	}
+	pages, err := s.ListPages(slug)
+	if err != nil {
+		return err
+	}
+	for _, page := range pages {
+		files = append(files, s.pageFiles(meta, page)...)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if isEncrypted(data) {
+			continue
+		}
+		if err := writeDocFile(file, data, aead); err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
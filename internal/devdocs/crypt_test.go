@@ -0,0 +1,149 @@
+package devdocs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func init() {
+	// Keep key derivation fast in tests
+	pbkdf2Iterations = 1000
+}
+
+func TestInstallEncrypted(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	index := &Index{Entries: []Entry{{Name: "secretAPI", Path: "internal/secret", Type: "Functions"}}}
+	db := map[string]string{"internal/secret": "<h1>Internal secret API</h1>"}
+	manifest := []Doc{{Slug: "internal", Mtime: 1}}
+
+	store := NewStore(WithDataDir(tmpDir), WithEncryption("hunter2"))
+	meta, err := store.Install("internal", index, db, manifest)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if meta.Encryption == nil {
+		t.Fatal("Install() meta.Encryption = nil, want encryption parameters")
+	}
+
+	// Nothing readable on disk, including the checksums of the plaintext
+	for _, file := range []string{
+		filepath.Join(tmpDir, "docs", "internal", "index.json"),
+		filepath.Join(tmpDir, "docs", "internal", checksumsFile),
+		store.ContentPath("internal", "internal/secret"),
+	} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret") {
+			t.Errorf("%s contains plaintext", file)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		passphrase string
+		wantErr    error
+	}{
+		{name: "Correct passphrase", passphrase: "hunter2"},
+		{name: "Missing passphrase", passphrase: "", wantErr: ErrPassphraseRequired},
+		{name: "Wrong passphrase", passphrase: "hunter3", wantErr: ErrWrongPassphrase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := NewStore(WithDataDir(tmpDir), WithPassphrase(tt.passphrase))
+
+			gotIndex, err := reader.LoadIndex("internal")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadIndex() error = %v, want %v", err, tt.wantErr)
+			}
+			content, err := reader.LoadContent("internal", "internal/secret")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadContent() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(gotIndex.Entries) != 1 || gotIndex.Entries[0].Name != "secretAPI" {
+				t.Errorf("LoadIndex() = %+v, want the secretAPI entry", gotIndex.Entries)
+			}
+			if content != db["internal/secret"] {
+				t.Errorf("LoadContent() = %q, want %q", content, db["internal/secret"])
+			}
+		})
+	}
+}
+
+func TestStoreEncrypt(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	db := map[string]string{"a": "<p>page a</p>", "b/c": "<p>page c</p>"}
+	plain := NewStore(WithDataDir(tmpDir))
+	if _, err := plain.Install("test", &Index{Entries: []Entry{{Name: "a", Path: "a"}}}, db, []Doc{{Slug: "test"}}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	if err := plain.Encrypt("test"); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Encrypt() without passphrase error = %v, want %v", err, ErrPassphraseRequired)
+	}
+
+	store := NewStore(WithDataDir(tmpDir), WithPassphrase("s3cret"))
+	if err := store.Encrypt("test"); err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	// Repeating is a no-op
+	if err := store.Encrypt("test"); err != nil {
+		t.Fatalf("Encrypt() second run error = %v", err)
+	}
+	if !store.IsEncrypted("test") {
+		t.Error("IsEncrypted() = false after Encrypt()")
+	}
+
+	for _, file := range []string{store.ContentPath("test", "b/c"), filepath.Join(store.DocDir("test"), checksumsFile)} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isEncrypted(data) {
+			t.Errorf("%s is not encrypted after Encrypt()", file)
+		}
+	}
+	if result, err := store.Verify("test"); err != nil || !result.OK() || result.Unverified {
+		t.Errorf("Verify() after Encrypt() = %+v, %v", result, err)
+	}
+
+	reader := NewStore(WithDataDir(tmpDir), WithPassphrase("s3cret"))
+	for path, want := range db {
+		got, err := reader.LoadContent("test", path)
+		if err != nil {
+			t.Fatalf("LoadContent(%q) error = %v", path, err)
+		}
+		if got != want {
+			t.Errorf("LoadContent(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	// Reinstalling keeps the doc encrypted
+	if _, err := reader.Install("test", &Index{}, db, []Doc{{Slug: "test"}}); err != nil {
+		t.Fatalf("Install() over encrypted doc error = %v", err)
+	}
+	if !reader.IsEncrypted("test") {
+		t.Error("IsEncrypted() = false after reinstalling an encrypted doc")
+	}
+	if got, err := reader.LoadContent("test", "a"); err != nil || got != db["a"] {
+		t.Errorf("LoadContent() after reinstall = %q, %v, want %q", got, err, db["a"])
+	}
+	if _, err := plain.Install("test", &Index{}, db, []Doc{{Slug: "test"}}); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Install() over encrypted doc without passphrase error = %v, want %v", err, ErrPassphraseRequired)
+	}
+}
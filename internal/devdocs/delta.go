@@ -0,0 +1,43 @@
+package devdocs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// deltaPages returns the checksums of the installed pages of slug that an
+// update to a new build can carry over instead of rewriting, or nil when no
+// page can be: the doc isn't installed, it is the same build (reinstalls
+// rewrite every page, e.g. to repair it), it has no checksums, or it stores
+// pages differently than the new install will.
+func (s *Store) deltaPages(prev *Meta, mtime int64, compressed bool) map[string]string {
+	if prev == nil || prev.Mtime == mtime || prev.Compressed != compressed {
+		return nil
+	}
+	checksums, err := s.loadChecksums(prev.Slug)
+	if err != nil {
+		return nil
+	}
+	return checksums
+}
+
+// linkPage places the installed file of an unchanged page in the staging
+// content directory. Hard links make this cheap; filesystems without them
+// get a copy.
+func linkPage(installedDir, contentDir, path string) error {
+	src := filepath.Join(installedDir, path+".html")
+	dst := filepath.Join(contentDir, path+".html")
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create content subdir: %w", err)
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
@@ -0,0 +1,70 @@
+package devdocs
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDeltaUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []StoreOption
+	}{
+		{name: "Plain"},
+		{name: "Compressed", opts: []StoreOption{WithCompression(true)}},
+		{name: "Encrypted", opts: []StoreOption{WithEncryption("secret")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewStore(append([]StoreOption{WithDataDir(t.TempDir())}, tt.opts...)...)
+			manifest := []Doc{{Slug: "go", Mtime: 1}}
+			if _, err := store.Install("go", &Index{}, map[string]string{"fmt": "<p>fmt</p>", "os": "<p>os</p>"}, manifest); err != nil {
+				t.Fatal(err)
+			}
+			stat := func(path string) os.FileInfo {
+				t.Helper()
+				info, err := os.Stat(store.ContentPath("go", path))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return info
+			}
+			fmtBefore, osBefore := stat("fmt"), stat("os")
+
+			// A new build changes one page and adds another
+			manifest[0].Mtime = 2
+			db := map[string]string{"fmt": "<p>fmt</p>", "os": "<p>os, updated</p>", "io": "<p>io</p>"}
+			if _, err := store.Install("go", &Index{}, db, manifest); err != nil {
+				t.Fatal(err)
+			}
+			if !os.SameFile(fmtBefore, stat("fmt")) {
+				t.Error("unchanged page was rewritten")
+			}
+			if os.SameFile(osBefore, stat("os")) {
+				t.Error("changed page was carried over")
+			}
+			for path, want := range db {
+				if got, err := store.LoadContent("go", path); err != nil || got != want {
+					t.Errorf("LoadContent(%q) = %q, %v, want %q", path, got, err, want)
+				}
+			}
+			if result, err := store.Verify("go"); err != nil || !result.OK() || result.Unverified {
+				t.Errorf("Verify() = %+v, %v after update", result, err)
+			}
+
+			// Reinstalling the same build rewrites every page
+			fmtBefore = stat("fmt")
+			if _, err := store.Install("go", &Index{}, db, manifest); err != nil {
+				t.Fatal(err)
+			}
+			if os.SameFile(fmtBefore, stat("fmt")) {
+				t.Error("reinstall of the same build carried over a page")
+			}
+		})
+	}
+}
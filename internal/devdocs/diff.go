@@ -0,0 +1,50 @@
+// Package devdocs provides types and client for interacting with the DevDocs API
+package devdocs
+
+import "sort"
+
+// IndexDiff lists the entries added and removed between two versions of an index.
+type IndexDiff struct {
+	Added   []Entry
+	Removed []Entry
+}
+
+// Empty reports whether the two versions have the same entries.
+func (d IndexDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// DiffIndex compares two versions of an index. Entries are matched by name and
+// type, so a page moving to a new path is not reported as a change.
+func DiffIndex(prev, next *Index) IndexDiff {
+	type key struct{ name, typ string }
+
+	prevKeys := make(map[key]bool, len(prev.Entries))
+	for _, e := range prev.Entries {
+		prevKeys[key{e.Name, e.Type}] = true
+	}
+	nextKeys := make(map[key]bool, len(next.Entries))
+	for _, e := range next.Entries {
+		nextKeys[key{e.Name, e.Type}] = true
+	}
+
+	var diff IndexDiff
+	for _, e := range next.Entries {
+		if !prevKeys[key{e.Name, e.Type}] {
+			diff.Added = append(diff.Added, e)
+		}
+	}
+	for _, e := range prev.Entries {
+		if !nextKeys[key{e.Name, e.Type}] {
+			diff.Removed = append(diff.Removed, e)
+		}
+	}
+
+	byName := func(entries []Entry) {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	}
+	byName(diff.Added)
+	byName(diff.Removed)
+
+	return diff
+}
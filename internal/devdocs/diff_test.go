@@ -0,0 +1,71 @@
+package devdocs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffIndex(t *testing.T) {
+	t.Parallel()
+
+	prev := &Index{Entries: []Entry{
+		{Name: "useState", Path: "hooks/usestate", Type: "Hooks"},
+		{Name: "useEffect", Path: "hooks/useeffect", Type: "Hooks"},
+		{Name: "createClass", Path: "legacy/createclass", Type: "Legacy"},
+	}}
+	next := &Index{Entries: []Entry{
+		{Name: "useState", Path: "reference/usestate", Type: "Hooks"}, // moved, not a change
+		{Name: "useEffect", Path: "hooks/useeffect", Type: "Hooks"},
+		{Name: "useOptimistic", Path: "hooks/useoptimistic", Type: "Hooks"},
+		{Name: "useActionState", Path: "hooks/useactionstate", Type: "Hooks"},
+	}}
+
+	tests := []struct {
+		name      string
+		prev      *Index
+		next      *Index
+		want      IndexDiff
+		wantEmpty bool
+	}{
+		{
+			name: "Added and removed entries",
+			prev: prev,
+			next: next,
+			want: IndexDiff{
+				Added: []Entry{
+					{Name: "useActionState", Path: "hooks/useactionstate", Type: "Hooks"},
+					{Name: "useOptimistic", Path: "hooks/useoptimistic", Type: "Hooks"},
+				},
+				Removed: []Entry{
+					{Name: "createClass", Path: "legacy/createclass", Type: "Legacy"},
+				},
+			},
+		},
+		{
+			name:      "Same index",
+			prev:      prev,
+			next:      prev,
+			wantEmpty: true,
+		},
+		{
+			name: "Fresh install",
+			prev: &Index{},
+			next: &Index{Entries: []Entry{{Name: "a", Path: "a", Type: "t"}}},
+			want: IndexDiff{Added: []Entry{{Name: "a", Path: "a", Type: "t"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := DiffIndex(tt.prev, tt.next)
+			if got.Empty() != tt.wantEmpty {
+				t.Errorf("DiffIndex().Empty() = %v, want %v", got.Empty(), tt.wantEmpty)
+			}
+			if !tt.wantEmpty && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffIndex() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Package devdocs provides types and client for interacting with the DevDocs API
+package devdocs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// retryBaseDelay is the backoff before the first retry; it doubles on each attempt.
+var retryBaseDelay = 500 * time.Millisecond
+
+// statusError is returned for unexpected HTTP status codes.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.code)
+}
+
+// decodeError wraps errors from decoding a response, which retrying can't fix.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+func (e *decodeError) Unwrap() error { return e.err }
+
+// retryable reports whether a failed request may succeed when retried:
+// network errors, server errors and rate limiting are, client errors are not.
+func retryable(err error) bool {
+	var de *decodeError
+	if errors.As(err, &de) {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code >= 500 || se.code == http.StatusTooManyRequests || se.code == http.StatusRequestedRangeNotSatisfiable
+	}
+	return true
+}
+
+// retry runs fn until it succeeds, fails with a non-retryable error, runs out
+// of attempts or ctx is cancelled, backing off exponentially between attempts.
+func (c *Client) retry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= c.retries || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(retryBaseDelay << attempt):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// get sends a GET request, returning an error for any status other than 200 or 206.
+func (c *Client) get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, &statusError{code: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// fetch downloads a (small) response body into memory, with retries.
+// progress, if not nil, is called as the body is read.
+func (c *Client) fetch(ctx context.Context, url string, progress ProgressFunc) ([]byte, error) {
+	var body []byte
+	err := c.retry(ctx, func() error {
+		resp, err := c.get(ctx, url, nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		var r io.Reader = resp.Body
+		if progress != nil {
+			r = &progressReader{r: resp.Body, phase: PhaseDownload, total: resp.ContentLength, progress: progress}
+		}
+		body, err = io.ReadAll(r)
+		return err
+	})
+	return body, err
+}
+
+// download saves url to path. Data is written to path.part first; when a
+// download is interrupted, the next attempt (or the next run) resumes from
+// where it stopped with an HTTP range request. The part file's validator
+// (ETag or Last-Modified) is sent as If-Range, so a changed file restarts
+// from scratch instead of being spliced.
+func (c *Client) download(ctx context.Context, url, path string, progress ProgressFunc) error {
+	partPath := path + ".part"
+	validatorPath := partPath + ".validator"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	err := c.retry(ctx, func() error {
+		var offset int64
+		if info, err := os.Stat(partPath); err == nil {
+			offset = info.Size()
+		}
+
+		header := http.Header{}
+		if offset > 0 {
+			header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			if validator, err := os.ReadFile(validatorPath); err == nil {
+				header.Set("If-Range", string(validator))
+			}
+		}
+
+		resp, err := c.get(ctx, url, header)
+		var se *statusError
+		if errors.As(err, &se) && se.code == http.StatusRequestedRangeNotSatisfiable {
+			// The part file doesn't match the remote file, start over
+			os.Remove(partPath)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		if resp.StatusCode == http.StatusOK {
+			// Full response: the server ignored or rejected the range
+			offset = 0
+			flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+			if err := saveValidator(validatorPath, resp.Header); err != nil {
+				return err
+			}
+		}
+
+		f, err := os.OpenFile(partPath, flags, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open download file: %w", err)
+		}
+		defer f.Close()
+
+		var body io.Reader = resp.Body
+		if progress != nil {
+			total := int64(-1)
+			if resp.ContentLength >= 0 {
+				total = offset + resp.ContentLength
+			}
+			body = &progressReader{r: resp.Body, phase: PhaseDownload, done: offset, total: total, progress: progress}
+		}
+
+		if _, err := io.Copy(f, body); err != nil {
+			return err
+		}
+		return f.Close()
+	})
+	if err != nil {
+		return err
+	}
+
+	os.Remove(validatorPath)
+	return os.Rename(partPath, path)
+}
+
+// saveValidator records the ETag (or Last-Modified) of a download for If-Range.
+func saveValidator(path string, header http.Header) error {
+	validator := header.Get("ETag")
+	if validator == "" {
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		os.Remove(path)
+		return nil
+	}
+	return os.WriteFile(path, []byte(validator), 0644)
+}
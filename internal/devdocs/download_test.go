@@ -0,0 +1,181 @@
+package devdocs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRetry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		failures     int // Requests answered with failStatus before succeeding
+		failStatus   int
+		retries      int
+		wantErr      bool
+		wantRequests int32
+	}{
+		{name: "Succeeds after server errors", failures: 2, failStatus: http.StatusServiceUnavailable, retries: 3, wantRequests: 3},
+		{name: "Rate limited", failures: 1, failStatus: http.StatusTooManyRequests, retries: 3, wantRequests: 2},
+		{name: "Gives up after retries", failures: 5, failStatus: http.StatusBadGateway, retries: 2, wantErr: true, wantRequests: 3},
+		{name: "Client errors are not retried", failures: 5, failStatus: http.StatusNotFound, retries: 3, wantErr: true, wantRequests: 1},
+		{name: "Retries disabled", failures: 1, failStatus: http.StatusInternalServerError, retries: 0, wantErr: true, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if n := atomic.AddInt32(&requests, 1); int(n) <= tt.failures {
+					w.WriteHeader(tt.failStatus)
+					return
+				}
+				w.Write([]byte(`{"entries": [{"name": "useState"}]}`))
+			}))
+			defer ts.Close()
+
+			client := NewClient(WithBaseURL(ts.URL), WithRetries(tt.retries))
+			_, err := client.FetchIndex(context.Background(), "react", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("server got %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestClientContextCancel(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient(WithBaseURL(ts.URL))
+	if _, err := client.FetchManifest(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchManifest() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`{"index": "<h1>React</h1>", "usestate": "<h1>useState</h1>"}`)
+	const etag = `"v1"`
+
+	tests := []struct {
+		name      string
+		partial   int    // Bytes already downloaded
+		validator string // Saved validator of the partial download
+		wantRange bool
+	}{
+		{name: "Fresh download", partial: 0},
+		{name: "Resume matching partial", partial: 20, validator: etag, wantRange: true},
+		{name: "Restart when the file changed", partial: 20, validator: `"v0"`, wantRange: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sawRange atomic.Bool
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") != "" {
+					sawRange.Store(true)
+				}
+				w.Header().Set("ETag", etag)
+				http.ServeContent(w, r, "db.json", time.Time{}, bytes.NewReader(content))
+			}))
+			defer ts.Close()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "react.db.json")
+			if tt.partial > 0 {
+				// A stale partial for the changed file must not be spliced in
+				partial := content[:tt.partial]
+				if tt.validator != etag {
+					partial = bytes.Repeat([]byte("x"), tt.partial)
+				}
+				if err := os.WriteFile(path+".part", partial, 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path+".part.validator", []byte(tt.validator), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var done, total int64
+			client := NewClient()
+			err := client.download(context.Background(), ts.URL+"/react/db.json", path, func(_ Phase, d, t int64) {
+				done, total = d, t
+			})
+			if err != nil {
+				t.Fatalf("download() error = %v", err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("download() wrote %q, want %q", got, content)
+			}
+			if sawRange.Load() != tt.wantRange {
+				t.Errorf("Range request = %v, want %v", sawRange.Load(), tt.wantRange)
+			}
+			if done != int64(len(content)) || total != int64(len(content)) {
+				t.Errorf("progress = %d/%d, want %d/%d", done, total, len(content), len(content))
+			}
+			if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+				t.Errorf("part file left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestStreamDBWithDownloadDir(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"index": "<h1>React</h1>", "usestate": "<h1>useState</h1>"}`))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	client := NewClient(WithBaseURL(ts.URL), WithDownloadDir(dir))
+
+	var pages []string
+	err := client.StreamDB(context.Background(), "react", 0, func(path, content string) error {
+		pages = append(pages, path)
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("StreamDB() error = %v", err)
+	}
+	if strings.Join(pages, ",") != "index,usestate" {
+		t.Errorf("StreamDB() pages = %v, want [index usestate]", pages)
+	}
+
+	// The completed download is removed once decoded
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("download directory not cleaned up: %v", entries)
+	}
+}
@@ -0,0 +1,50 @@
+package devdocs
+
+import "strings"
+
+// FilterIndex returns the entries of index whose type or path starts with one
+// of the filters, e.g. "CSS" or "web/api/". Types compare case-insensitively.
+// The types list is trimmed to the types that are left.
+func FilterIndex(index *Index, only []string) *Index {
+	filtered := &Index{Entries: []Entry{}, Types: []Type{}}
+	kept := make(map[string]bool)
+	for _, entry := range index.Entries {
+		if matchesFilter(entry, only) {
+			filtered.Entries = append(filtered.Entries, entry)
+			kept[entry.Type] = true
+		}
+	}
+	for _, t := range index.Types {
+		if kept[t.Name] {
+			filtered.Types = append(filtered.Types, t)
+		}
+	}
+	return filtered
+}
+
+func matchesFilter(entry Entry, only []string) bool {
+	typ := strings.ToLower(entry.Type)
+	for _, filter := range only {
+		if strings.HasPrefix(typ, strings.ToLower(filter)) || strings.HasPrefix(entry.Path, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSource skips the pages that no entry in index refers to.
+func filterSource(source ContentSource, index *Index) ContentSource {
+	pages := make(map[string]bool, len(index.Entries))
+	for _, entry := range index.Entries {
+		page, _, _ := strings.Cut(entry.Path, "#")
+		pages[page] = true
+	}
+	return func(fn ContentFunc) error {
+		return source(func(path, content string) error {
+			if !pages[path] {
+				return nil
+			}
+			return fn(path, content)
+		})
+	}
+}
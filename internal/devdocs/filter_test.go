@@ -0,0 +1,114 @@
+package devdocs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterIndex(t *testing.T) {
+	t.Parallel()
+
+	index := &Index{
+		Entries: []Entry{
+			{Name: "color", Path: "css/color", Type: "CSS Properties"},
+			{Name: "Array", Path: "javascript/global_objects/array", Type: "JavaScript"},
+			{Name: "fetch", Path: "web/api/fetch", Type: "Web APIs"},
+			{Name: "Array.map", Path: "javascript/global_objects/array#map", Type: "JavaScript"},
+		},
+		Types: []Type{{Name: "CSS Properties", Count: 1}, {Name: "JavaScript", Count: 2}, {Name: "Web APIs", Count: 1}},
+	}
+
+	tests := []struct {
+		name      string
+		only      []string
+		wantPaths []string
+		wantTypes []string
+	}{
+		{
+			name:      "Type prefix, case-insensitive",
+			only:      []string{"css"},
+			wantPaths: []string{"css/color"},
+			wantTypes: []string{"CSS Properties"},
+		},
+		{
+			name:      "Type and path prefix",
+			only:      []string{"javascript", "web/api/"},
+			wantPaths: []string{"javascript/global_objects/array", "web/api/fetch", "javascript/global_objects/array#map"},
+			wantTypes: []string{"JavaScript", "Web APIs"},
+		},
+		{
+			name:      "No match",
+			only:      []string{"html"},
+			wantPaths: []string{},
+			wantTypes: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := FilterIndex(index, tt.only)
+			paths := []string{}
+			for _, e := range got.Entries {
+				paths = append(paths, e.Path)
+			}
+			types := []string{}
+			for _, typ := range got.Types {
+				types = append(types, typ.Name)
+			}
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("FilterIndex() paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if !reflect.DeepEqual(types, tt.wantTypes) {
+				t.Errorf("FilterIndex() types = %v, want %v", types, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestPartialInstall(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	manifest := []Doc{{Slug: "mdn", Mtime: 1}}
+	index := &Index{Entries: []Entry{
+		{Name: "color", Path: "css/color", Type: "CSS"},
+		{Name: "Array.map", Path: "javascript/array#map", Type: "JavaScript"},
+	}}
+	db := map[string]string{"css/color": "<p>color</p>", "javascript/array": "<p>Array</p>", "html/div": "<p>div</p>"}
+
+	store := NewStore(WithDataDir(dataDir), WithOnly([]string{"javascript"}))
+	meta, err := store.Install("mdn", index, db, manifest)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !reflect.DeepEqual(meta.Only, []string{"javascript"}) {
+		t.Errorf("Install() Only = %v, want [javascript]", meta.Only)
+	}
+
+	pages, err := store.ListPages("mdn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pages, []string{"javascript/array"}) {
+		t.Errorf("ListPages() = %v, want only the javascript page", pages)
+	}
+
+	// A reinstall without filters keeps the partial install's filters
+	manifest[0].Mtime = 2
+	if _, err := NewStore(WithDataDir(dataDir)).Install("mdn", index, db, manifest); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.LoadIndex("mdn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Entries) != 1 || got.Entries[0].Name != "Array.map" {
+		t.Errorf("LoadIndex() after reinstall = %+v, want only Array.map", got.Entries)
+	}
+
+	if _, err := NewStore(WithDataDir(dataDir), WithOnly([]string{"html"})).Install("mdn", index, db, manifest); err == nil {
+		t.Error("Install() with a filter matching nothing should fail")
+	}
+}
@@ -0,0 +1,76 @@
+package devdocs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileLock is an advisory lock on a file, shared by every dsearch process
+// using the same directories (e.g. a search and a cron upgrade).
+type fileLock struct {
+	f *os.File
+}
+
+// acquireLock blocks until it holds the exclusive lock on path, creating the
+// lock file if needed. Lock files are kept, since removing them would race
+// with processes waiting on them.
+func acquireLock(path string) (*fileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &fileLock{f: f}, nil
+}
+
+// release unlocks and closes the lock file.
+func (l *fileLock) release() {
+	unlockFile(l.f)
+	l.f.Close()
+}
+
+// lockDoc locks an installed doc against concurrent installs and changes.
+// The lock file lives next to the doc, as a dot file ListInstalled skips.
+func (s *Store) lockDoc(slug string) (*fileLock, error) {
+	return acquireLock(filepath.Join(s.dataDir, "docs", "."+slug+".lock"))
+}
+
+// lockManifest locks the cached manifest and its validators against concurrent writes.
+func (s *Store) lockManifest() (*fileLock, error) {
+	return acquireLock(filepath.Join(s.cacheDir, "manifest.lock"))
+}
+
+// lockUsage locks the usage stats against concurrent updates.
+func (s *Store) lockUsage() (*fileLock, error) {
+	return acquireLock(filepath.Join(s.dataDir, "usage.lock"))
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so concurrent readers see either the old or the new file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,102 @@
+package devdocs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.lock")
+	first, err := acquireLock(path)
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+
+	acquired := make(chan *fileLock)
+	go func() {
+		second, err := acquireLock(path)
+		if err != nil {
+			t.Errorf("acquireLock() error = %v", err)
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second acquireLock() returned while the lock was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first.release()
+	select {
+	case second := <-acquired:
+		second.release()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second acquireLock() did not return after release")
+	}
+}
+
+func TestConcurrentInstalls(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	const installs = 8
+
+	var wg sync.WaitGroup
+	for i := range installs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate stores, like separate processes
+			store := NewStore(WithDataDir(dataDir))
+			manifest := []Doc{{Slug: "go", Mtime: int64(i)}}
+			index := &Index{Entries: []Entry{{Name: fmt.Sprintf("v%d", i), Path: "fmt"}}}
+			db := map[string]string{"fmt": fmt.Sprintf("<p>v%d</p>", i)}
+			if _, err := store.Install("go", index, db, manifest); err != nil {
+				t.Errorf("Install() error = %v", err)
+			}
+		}()
+	}
+
+	// Readers see a complete install at any time
+	store := NewStore(WithDataDir(dataDir))
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		if index, err := store.LoadIndex("go"); err == nil && len(index.Entries) != 1 {
+			t.Errorf("LoadIndex() during installs = %+v, want one entry", index.Entries)
+		}
+	}
+
+	// The index, content and meta come from the same install
+	meta, err := store.LoadMeta("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := store.LoadIndex("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := store.LoadContent("go", "fmt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("v%d", meta.Mtime)
+	if index.Entries[0].Name != want || content != "<p>"+want+"</p>" {
+		t.Errorf("after concurrent installs: meta mtime %d, index %q, content %q", meta.Mtime, index.Entries[0].Name, content)
+	}
+	assertNoStaging(t, dataDir)
+}
@@ -0,0 +1,95 @@
+package devdocs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LockVersion is the lockfile format written by WriteLock.
+const LockVersion = 1
+
+// Lock records a set of installed docs and their builds, so the same docs can
+// be installed on another machine.
+type Lock struct {
+	Version int         `json:"version"`
+	Docs    []LockedDoc `json:"docs"`
+}
+
+// LockedDoc is one doc in a lockfile. Mtime identifies the DevDocs build.
+type LockedDoc struct {
+	Slug    string `json:"slug"`
+	Release string `json:"release,omitempty"`
+	Mtime   int64  `json:"mtime"`
+}
+
+// ErrPinnedBuild is returned for locked docs pinned at another build, which
+// installs skip.
+var ErrPinnedBuild = errors.New("pinned at a different build than the lockfile")
+
+// Locked reports whether a locked doc is installed at its locked build. Docs
+// pinned at another build return ErrPinnedBuild: installing the lockfile would
+// leave them at the wrong build.
+func (s *Store) Locked(want LockedDoc) (bool, error) {
+	meta, err := s.LoadMeta(want.Slug)
+	switch {
+	case err != nil:
+		return false, nil
+	case meta.Mtime == want.Mtime:
+		return true, nil
+	case meta.Pinned:
+		return false, fmt.Errorf("%s: %w", want.Slug, ErrPinnedBuild)
+	}
+	return false, nil
+}
+
+// Lock returns a lockfile for the installed docs. Releases are taken from
+// manifest when it lists the installed build.
+func (s *Store) Lock(manifest []Doc) (*Lock, error) {
+	releases := make(map[string]string, len(manifest))
+	for _, doc := range manifest {
+		releases[fmt.Sprintf("%s@%d", doc.Slug, doc.Mtime)] = doc.Release
+	}
+
+	lock := &Lock{Version: LockVersion, Docs: []LockedDoc{}}
+	for _, slug := range s.ListInstalled() {
+		meta, err := s.LoadMeta(slug)
+		if err != nil {
+			return nil, err
+		}
+		lock.Docs = append(lock.Docs, LockedDoc{
+			Slug:    slug,
+			Release: releases[fmt.Sprintf("%s@%d", slug, meta.Mtime)],
+			Mtime:   meta.Mtime,
+		})
+	}
+	return lock, nil
+}
+
+// WriteLock writes lock as indented JSON.
+func WriteLock(w io.Writer, lock *Lock) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(lock); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// ReadLock reads a lockfile written by WriteLock.
+func ReadLock(r io.Reader) (*Lock, error) {
+	var lock Lock
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+	if lock.Version != LockVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d (want %d)", lock.Version, LockVersion)
+	}
+	for _, doc := range lock.Docs {
+		if doc.Slug == "" {
+			return nil, fmt.Errorf("lockfile has a doc without a slug")
+		}
+	}
+	return &lock, nil
+}
@@ -0,0 +1,115 @@
+package devdocs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLock(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	installed := []Doc{{Slug: "go", Mtime: 10, Release: "1.22"}, {Slug: "react~18", Mtime: 20, Release: "18.2.0"}}
+	for _, doc := range installed {
+		if _, err := store.Install(doc.Slug, &Index{}, nil, installed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The catalog has a newer build of react~18, whose release must not be recorded
+	manifest := []Doc{{Slug: "go", Mtime: 10, Release: "1.22"}, {Slug: "react~18", Mtime: 30, Release: "18.3.1"}}
+	lock, err := store.Lock(manifest)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	want := &Lock{Version: LockVersion, Docs: []LockedDoc{
+		{Slug: "go", Release: "1.22", Mtime: 10},
+		{Slug: "react~18", Mtime: 20},
+	}}
+	if !reflect.DeepEqual(lock, want) {
+		t.Errorf("Lock() = %+v, want %+v", lock, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLock(&buf, lock); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+	got, err := ReadLock(&buf)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLock() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLocked(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	installed := []Doc{{Slug: "go", Mtime: 10}, {Slug: "react", Mtime: 10}, {Slug: "vue", Mtime: 10}}
+	for _, doc := range installed {
+		if _, err := store.Install(doc.Slug, &Index{}, nil, installed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SetPinned("react", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetPinned("vue", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		want    LockedDoc
+		locked  bool
+		wantErr error
+	}{
+		{name: "Locked build", want: LockedDoc{Slug: "go", Mtime: 10}, locked: true},
+		{name: "Other build", want: LockedDoc{Slug: "go", Mtime: 20}},
+		{name: "Not installed", want: LockedDoc{Slug: "rust", Mtime: 10}},
+		{name: "Pinned at locked build", want: LockedDoc{Slug: "vue", Mtime: 10}, locked: true},
+		{name: "Pinned at stale build", want: LockedDoc{Slug: "react", Mtime: 20}, wantErr: ErrPinnedBuild},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			locked, err := store.Locked(tt.want)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Locked() error = %v, want %v", err, tt.wantErr)
+			}
+			if locked != tt.locked {
+				t.Errorf("Locked() = %v, want %v", locked, tt.locked)
+			}
+		})
+	}
+}
+
+func TestReadLockErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Malformed", input: `{"version": 1, "docs": [`},
+		{name: "Unknown version", input: `{"version": 2, "docs": []}`},
+		{name: "Missing slug", input: `{"version": 1, "docs": [{"mtime": 1}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ReadLock(strings.NewReader(tt.input)); err == nil {
+				t.Error("ReadLock() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package devdocs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestValidatorsFile holds the HTTP validators of the cached manifest.
+const manifestValidatorsFile = "manifest.validators.json"
+
+// ErrNotModified is returned when a conditional request finds the cached copy current.
+var ErrNotModified = errors.New("not modified")
+
+// CacheValidators are the HTTP validators of a cached response, sent back to
+// revalidate it with a conditional request.
+type CacheValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// FetchManifestIfChanged fetches the manifest unless the copy described by v
+// is still current, in which case it returns ErrNotModified. It also returns
+// the validators of the fetched manifest.
+func (c *Client) FetchManifestIfChanged(ctx context.Context, v CacheValidators) ([]Doc, CacheValidators, error) {
+	url := fmt.Sprintf("%s/docs.json", c.manifestURL)
+
+	header := http.Header{}
+	if v.ETag != "" {
+		header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	var body []byte
+	var next CacheValidators
+	err := c.retry(ctx, func() error {
+		resp, err := c.get(ctx, url, header)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		next = CacheValidators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+		body, err = io.ReadAll(resp.Body)
+		return err
+	})
+	var se *statusError
+	if errors.As(err, &se) && se.code == http.StatusNotModified {
+		return nil, v, ErrNotModified
+	}
+	if err != nil {
+		return nil, next, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+
+	var docs []Doc
+	if err := json.Unmarshal(body, &docs); err != nil {
+		return nil, next, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	return docs, next, nil
+}
+
+// LoadManifestValidators returns the validators of the cached manifest, or
+// zero validators when none were saved.
+func (s *Store) LoadManifestValidators() CacheValidators {
+	var v CacheValidators
+	data, err := os.ReadFile(filepath.Join(s.cacheDir, manifestValidatorsFile))
+	if err == nil {
+		_ = json.Unmarshal(data, &v)
+	}
+	return v
+}
+
+// SaveManifestValidators saves the validators of the cached manifest. Call it
+// after SaveManifest, which discards the previous ones.
+func (s *Store) SaveManifestValidators(v CacheValidators) error {
+	if v == (CacheValidators{}) {
+		return nil
+	}
+
+	lock, err := s.lockManifest()
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+	return writeJSON(filepath.Join(s.cacheDir, manifestValidatorsFile), v)
+}
+
+// ManifestAge returns how long ago the cached manifest was saved or revalidated.
+func (s *Store) ManifestAge() (time.Duration, error) {
+	info, err := os.Stat(filepath.Join(s.cacheDir, "manifest.json"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat manifest: %w", err)
+	}
+	return time.Since(info.ModTime()), nil
+}
+
+// TouchManifest marks the cached manifest as revalidated now.
+func (s *Store) TouchManifest() error {
+	lock, err := s.lockManifest()
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	now := time.Now()
+	return os.Chtimes(filepath.Join(s.cacheDir, "manifest.json"), now, now)
+}
+
+// IsDocPattern reports whether s is a glob pattern rather than a slug.
+func IsDocPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// MatchDocs returns the docs in manifest whose slug matches the glob pattern
+// (e.g. "python~*" or "rust*"), in manifest order.
+func MatchDocs(manifest []Doc, pattern string) ([]Doc, error) {
+	var matches []Doc
+	for _, doc := range manifest {
+		ok, err := path.Match(pattern, doc.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if ok {
+			matches = append(matches, doc)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,139 @@
+package devdocs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFetchManifestIfChanged(t *testing.T) {
+	t.Parallel()
+
+	const etag = `"v1"`
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Write([]byte(`[{"name": "Go", "slug": "go"}]`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(WithBaseURL(ts.URL))
+
+	docs, v, err := client.FetchManifestIfChanged(context.Background(), CacheValidators{})
+	if err != nil {
+		t.Fatalf("FetchManifestIfChanged() error = %v", err)
+	}
+	if len(docs) != 1 || docs[0].Slug != "go" {
+		t.Errorf("FetchManifestIfChanged() = %+v, want the go doc", docs)
+	}
+	want := CacheValidators{ETag: etag, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"}
+	if v != want {
+		t.Errorf("FetchManifestIfChanged() validators = %+v, want %+v", v, want)
+	}
+
+	// Revalidating with the validators is answered with 304, without retries
+	_, got, err := client.FetchManifestIfChanged(context.Background(), v)
+	if !errors.Is(err, ErrNotModified) {
+		t.Fatalf("FetchManifestIfChanged() error = %v, want ErrNotModified", err)
+	}
+	if got != v {
+		t.Errorf("FetchManifestIfChanged() validators = %+v, want %+v", got, v)
+	}
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2", requests)
+	}
+}
+
+func TestManifestValidators(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	if got := store.LoadManifestValidators(); got != (CacheValidators{}) {
+		t.Errorf("LoadManifestValidators() without a manifest = %+v, want none", got)
+	}
+
+	v := CacheValidators{ETag: `"abc"`}
+	if err := store.SaveManifest([]Doc{{Slug: "go"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveManifestValidators(v); err != nil {
+		t.Fatalf("SaveManifestValidators() error = %v", err)
+	}
+	if got := store.LoadManifestValidators(); got != v {
+		t.Errorf("LoadManifestValidators() = %+v, want %+v", got, v)
+	}
+
+	// A manifest saved without validators must not be revalidated with stale ones
+	if err := store.SaveManifest([]Doc{{Slug: "rust"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.LoadManifestValidators(); got != (CacheValidators{}) {
+		t.Errorf("LoadManifestValidators() after SaveManifest() = %+v, want none", got)
+	}
+}
+
+func TestManifestAge(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()), WithCacheDir(t.TempDir()))
+	if _, err := store.ManifestAge(); err == nil {
+		t.Error("ManifestAge() without a manifest should fail")
+	}
+
+	if err := store.SaveManifest([]Doc{{Slug: "go"}}); err != nil {
+		t.Fatal(err)
+	}
+	age, err := store.ManifestAge()
+	if err != nil {
+		t.Fatalf("ManifestAge() error = %v", err)
+	}
+	if age < 0 || age > time.Minute {
+		t.Errorf("ManifestAge() = %v, want a fresh manifest", age)
+	}
+}
+
+func TestMatchDocs(t *testing.T) {
+	t.Parallel()
+
+	manifest := []Doc{{Slug: "python~3.12"}, {Slug: "python~3.11"}, {Slug: "rust"}, {Slug: "react~18"}, {Slug: "react"}}
+
+	tests := []struct {
+		pattern string
+		want    []string
+		wantErr bool
+	}{
+		{pattern: "python~*", want: []string{"python~3.12", "python~3.11"}},
+		{pattern: "r*", want: []string{"rust", "react~18", "react"}},
+		{pattern: "react", want: []string{"react"}},
+		{pattern: "go*", want: nil},
+		{pattern: "[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			t.Parallel()
+
+			docs, err := MatchDocs(manifest, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MatchDocs(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+			var got []string
+			for _, doc := range docs {
+				got = append(got, doc.Slug)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchDocs(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
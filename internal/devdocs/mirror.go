@@ -0,0 +1,166 @@
+package devdocs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MirrorManifestFile lists the docs of an offline mirror directory, next to
+// their bundles. It has the format of the DevDocs docs.json, so the directory
+// can also be served as manifest_url and bundle_url.
+const MirrorManifestFile = "docs.json"
+
+// ReadMirrorManifest reads the docs.json of a mirror directory. Mirrors come
+// from other machines, so every slug is validated before it is used as a path.
+func ReadMirrorManifest(dir string) ([]Doc, error) {
+	path := filepath.Join(dir, MirrorManifestFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror catalog: %w", err)
+	}
+	var docs []Doc
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("failed to parse mirror catalog %s: %w", path, err)
+	}
+	for _, doc := range docs {
+		if err := ValidateSlug(doc.Slug); err != nil {
+			return nil, fmt.Errorf("mirror catalog %s: %w", path, err)
+		}
+	}
+	return docs, nil
+}
+
+var (
+	// ErrUpToDate is returned when importing a doc installed at the same or a newer build.
+	ErrUpToDate = errors.New("already installed at the same or a newer build")
+	// ErrPinned is returned when importing over a pinned doc without force.
+	ErrPinned = errors.New("doc is pinned")
+)
+
+// ImportMirrorDoc installs one doc from its bundle in a mirror directory, with
+// its entry from the mirror's docs. Docs installed at the same or a newer
+// build return ErrUpToDate, and pinned docs return ErrPinned unless force is
+// set, as for upgrades.
+func (s *Store) ImportMirrorDoc(dir, slug string, docs []Doc, force bool) (*Doc, error) {
+	if err := ValidateSlug(slug); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(dir, slug+".tar.gz"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no bundle for %s in %s", slug, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bundle, err := ReadBundle(f)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Slug != slug {
+		return nil, fmt.Errorf("bundle for %s contains %s", slug, bundle.Slug)
+	}
+	if meta, err := s.LoadMeta(slug); err == nil {
+		switch {
+		case meta.Mtime >= bundle.Mtime:
+			return nil, ErrUpToDate
+		case meta.Pinned && !force:
+			return nil, ErrPinned
+		}
+	}
+
+	doc := Doc{Name: slug, Slug: slug}
+	for _, d := range docs {
+		if d.Slug == slug {
+			doc = d
+		}
+	}
+	doc.Mtime = bundle.Mtime
+
+	if _, err := s.InstallFrom(slug, bundle.Index, MapSource(bundle.DB), []Doc{doc}, WithBundle(bundle), WithSource(SourceMirror)); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// MirrorDocs returns catalog entries for installed docs, for a mirror's
+// docs.json. Entries are taken from the cached manifest when it lists the
+// doc, with the mtime and size of the installed build.
+func (s *Store) MirrorDocs(slugs []string) ([]Doc, error) {
+	manifest, _ := s.LoadManifest()
+	bySlug := make(map[string]Doc, len(manifest))
+	for _, doc := range manifest {
+		bySlug[doc.Slug] = doc
+	}
+
+	docs := make([]Doc, 0, len(slugs))
+	for _, slug := range slugs {
+		meta, err := s.LoadMeta(slug)
+		if err != nil {
+			return nil, err
+		}
+		doc, ok := bySlug[slug]
+		if !ok {
+			doc = Doc{Name: slug, Slug: slug}
+		}
+		if doc.Mtime != meta.Mtime {
+			// The catalog describes another build
+			doc.Release = ""
+		}
+		doc.Mtime = meta.Mtime
+		doc.DBSize = meta.DBSize
+		if meta.Attribution != "" {
+			doc.Attribution = meta.Attribution
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// MergeManifest adds docs missing from the cached manifest, e.g. docs
+// imported from an offline mirror, so they are listed by name. The cache
+// keeps its age, and a manifest created here counts as stale, so the next
+// command that can reach DevDocs still fetches the full catalog.
+func (s *Store) MergeManifest(docs []Doc) error {
+	lock, err := s.lockManifest()
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	manifestPath := filepath.Join(s.cacheDir, "manifest.json")
+	modTime := time.Unix(0, 0)
+	if info, err := os.Stat(manifestPath); err == nil {
+		modTime = info.ModTime()
+	}
+
+	manifest, _ := s.LoadManifest()
+	known := make(map[string]bool, len(manifest))
+	for _, doc := range manifest {
+		known[doc.Slug] = true
+	}
+	added := 0
+	for _, doc := range docs {
+		if !known[doc.Slug] {
+			known[doc.Slug] = true
+			manifest = append(manifest, doc)
+			added++
+		}
+	}
+	if added == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := writeJSON(manifestPath, manifest); err != nil {
+		return fmt.Errorf("failed to save manifest: %w", err)
+	}
+	return os.Chtimes(manifestPath, modTime, modTime)
+}
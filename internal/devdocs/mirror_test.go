@@ -0,0 +1,173 @@
+package devdocs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMirrorDocs(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()), WithCacheDir(t.TempDir()))
+	installed := []Doc{{Name: "Go", Slug: "go", Release: "1.22", Mtime: 10, DBSize: 100}, {Slug: "internal", Mtime: 5, DBSize: 50, Attribution: "&copy; Acme"}}
+	for _, doc := range installed {
+		if _, err := store.Install(doc.Slug, &Index{}, nil, installed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The catalog has moved on to a newer Go build
+	if err := store.SaveManifest([]Doc{{Name: "Go", Slug: "go", Release: "1.23", Mtime: 20, DBSize: 120, Alias: "golang"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := store.MirrorDocs([]string{"go", "internal"})
+	if err != nil {
+		t.Fatalf("MirrorDocs() error = %v", err)
+	}
+	want := []Doc{
+		{Name: "Go", Slug: "go", Mtime: 10, DBSize: 100, Alias: "golang"},
+		{Name: "internal", Slug: "internal", Mtime: 5, DBSize: 50, Attribution: "&copy; Acme"},
+	}
+	if !reflect.DeepEqual(docs, want) {
+		t.Errorf("MirrorDocs() = %+v, want %+v", docs, want)
+	}
+}
+
+func TestMergeManifest(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()), WithCacheDir(t.TempDir()))
+
+	// A manifest created by a merge is stale
+	if err := store.MergeManifest([]Doc{{Slug: "go", Mtime: 1}}); err != nil {
+		t.Fatalf("MergeManifest() error = %v", err)
+	}
+	if age, err := store.ManifestAge(); err != nil || age < 24*time.Hour {
+		t.Errorf("ManifestAge() after merge = %v, %v, want a stale manifest", age, err)
+	}
+
+	// Merging into a fresh manifest keeps it fresh and keeps its entries
+	if err := store.SaveManifest([]Doc{{Slug: "go", Mtime: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MergeManifest([]Doc{{Slug: "go", Mtime: 1}, {Slug: "rust", Mtime: 3}}); err != nil {
+		t.Fatalf("MergeManifest() error = %v", err)
+	}
+	manifest, err := store.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Doc{{Slug: "go", Mtime: 2}, {Slug: "rust", Mtime: 3}}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Errorf("LoadManifest() after merge = %+v, want %+v", manifest, want)
+	}
+	if age, err := store.ManifestAge(); err != nil || age > time.Minute {
+		t.Errorf("ManifestAge() after merge = %v, %v, want a fresh manifest", age, err)
+	}
+}
+
+func TestReadMirrorManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		catalog string
+		want    []Doc
+		wantErr bool
+	}{
+		{name: "Valid", catalog: `[{"slug":"go","mtime":1},{"slug":"python~3.12","mtime":2}]`, want: []Doc{{Slug: "go", Mtime: 1}, {Slug: "python~3.12", Mtime: 2}}},
+		{name: "Parent directory", catalog: `[{"slug":"go"},{"slug":"../../x"}]`, wantErr: true},
+		{name: "Absolute path", catalog: `[{"slug":"/etc/x"}]`, wantErr: true},
+		{name: "Backslash", catalog: `[{"slug":"..\\x"}]`, wantErr: true},
+		{name: "Hidden", catalog: `[{"slug":".go.lock"}]`, wantErr: true},
+		{name: "Empty", catalog: `[{"slug":""}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, MirrorManifestFile), []byte(tt.catalog), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadMirrorManifest(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadMirrorManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMirrorManifest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportMirrorDoc(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "go.tar.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := &Bundle{Slug: "go", Mtime: 2, Index: &Index{Entries: []Entry{{Name: "fmt", Path: "fmt"}}}, DB: map[string]string{"fmt": "<p>fmt</p>"}}
+	if err := WriteBundle(f, bundle); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	docs := []Doc{{Name: "Go", Slug: "go", Mtime: 2}}
+
+	tests := []struct {
+		name      string
+		installed int64 // Mtime of the installed build, 0 if not installed
+		pinned    bool
+		force     bool
+		wantErr   error
+	}{
+		{name: "Not installed"},
+		{name: "Older build", installed: 1},
+		{name: "Same build", installed: 2, wantErr: ErrUpToDate},
+		{name: "Pinned older build", installed: 1, pinned: true, wantErr: ErrPinned},
+		{name: "Pinned older build with force", installed: 1, pinned: true, force: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewStore(WithDataDir(t.TempDir()))
+			if tt.installed > 0 {
+				if _, err := store.Install("go", &Index{}, nil, []Doc{{Slug: "go", Mtime: tt.installed}}); err != nil {
+					t.Fatal(err)
+				}
+				if err := store.SetPinned("go", tt.pinned); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			doc, err := store.ImportMirrorDoc(dir, "go", docs, tt.force)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportMirrorDoc() error = %v, want %v", err, tt.wantErr)
+			}
+			meta, metaErr := store.LoadMeta("go")
+			if tt.wantErr != nil {
+				if metaErr != nil || meta.Mtime != tt.installed {
+					t.Errorf("ImportMirrorDoc() replaced the installed build: %+v, %v", meta, metaErr)
+				}
+				return
+			}
+			if doc == nil || doc.Name != "Go" || metaErr != nil || meta.Mtime != 2 || meta.Source != SourceMirror {
+				t.Errorf("ImportMirrorDoc() = %+v, meta %+v, %v", doc, meta, metaErr)
+			}
+			if meta.Pinned != tt.pinned {
+				t.Errorf("ImportMirrorDoc() Pinned = %v, want %v", meta.Pinned, tt.pinned)
+			}
+		})
+	}
+}
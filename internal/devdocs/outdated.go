@@ -0,0 +1,43 @@
+package devdocs
+
+import (
+	"sort"
+)
+
+// Update describes an installed doc with a newer release in the manifest.
+type Update struct {
+	Meta *Meta // What is installed
+	Doc  *Doc  // The latest release
+}
+
+// SizeDelta returns how much the download grows (or shrinks) with the update.
+func (u Update) SizeDelta() int64 {
+	return u.Doc.DBSize - u.Meta.DBSize
+}
+
+// Outdated compares installed docs against manifest and returns those with a
+// newer release, sorted by slug. Docs missing from the manifest are skipped.
+func (s *Store) Outdated(manifest []Doc) []Update {
+	docsBySlug := make(map[string]*Doc, len(manifest))
+	for i := range manifest {
+		docsBySlug[manifest[i].Slug] = &manifest[i]
+	}
+
+	var updates []Update
+	for _, slug := range s.ListInstalled() {
+		doc, ok := docsBySlug[slug]
+		if !ok {
+			continue
+		}
+		meta, err := s.LoadMeta(slug)
+		if err != nil || meta.Mtime >= doc.Mtime {
+			continue
+		}
+		updates = append(updates, Update{Meta: meta, Doc: doc})
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Doc.Slug < updates[j].Doc.Slug
+	})
+	return updates
+}
@@ -0,0 +1,40 @@
+package devdocs
+
+import (
+	"testing"
+)
+
+func TestOutdated(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
+
+	installed := []Doc{
+		{Name: "Go", Slug: "go", Mtime: 100, DBSize: 1000},
+		{Name: "React", Slug: "react", Mtime: 200, DBSize: 2000},
+		{Name: "Internal", Slug: "internal", Mtime: 100, DBSize: 10},
+	}
+	for _, doc := range installed {
+		if _, err := store.Install(doc.Slug, &Index{}, nil, installed); err != nil {
+			t.Fatalf("Install(%s) error = %v", doc.Slug, err)
+		}
+	}
+
+	manifest := []Doc{
+		{Name: "React", Slug: "react", Mtime: 300, DBSize: 2500},
+		{Name: "Go", Slug: "go", Mtime: 100, DBSize: 1000},
+		{Name: "Rust", Slug: "rust", Mtime: 500, DBSize: 3000},
+	}
+
+	updates := store.Outdated(manifest)
+	if len(updates) != 1 {
+		t.Fatalf("Outdated() returned %d updates, want 1", len(updates))
+	}
+	if got := updates[0].Doc.Slug; got != "react" {
+		t.Errorf("Outdated()[0] = %q, want react", got)
+	}
+	if got := updates[0].SizeDelta(); got != 500 {
+		t.Errorf("SizeDelta() = %d, want 500", got)
+	}
+}
@@ -0,0 +1,111 @@
+package devdocs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/icampana/dsearch/internal/render"
+)
+
+// Post-processing steps for the content pages of new installs. They run once
+// at install time, so rendering at search time has less to do.
+const (
+	StepStripStyles = "strip-styles" // Remove style elements and inline styles
+	StepMinify      = "minify"       // Drop comments and collapse whitespace
+	StepMarkdown    = "markdown"     // Pre-render pages to markdown (must be last)
+)
+
+// originalsDir holds the original HTML of post-processed pages, when kept.
+const originalsDir = "originals"
+
+// ErrNoHTML is returned for pages that were converted to markdown at install
+// time without keeping their original HTML.
+var ErrNoHTML = errors.New("page was converted to markdown and its original HTML was not kept")
+
+// processPage runs a content page through post-processing steps in order.
+func processPage(content string, steps []string) (string, error) {
+	data := []byte(content)
+	for _, step := range steps {
+		var err error
+		switch step {
+		case StepStripStyles:
+			data, err = render.StripStyles(data)
+		case StepMinify:
+			data, err = render.Minify(data)
+		case StepMarkdown:
+			var md string
+			md, err = render.New(render.FormatMD).Render(data)
+			data = []byte(md)
+		default:
+			return "", fmt.Errorf("unknown post-processing step %q", step)
+		}
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", step, err)
+		}
+	}
+	return string(data), nil
+}
+
+// processSteps returns the post-processing steps configured for slug, by slug
+// or by doc name, and whether any are configured. An empty list turns
+// post-processing off for the doc.
+func (s *Store) processSteps(slug string) ([]string, bool) {
+	if steps, ok := s.process[slug]; ok {
+		return steps, true
+	}
+	name, _, _ := strings.Cut(slug, "~")
+	steps, ok := s.process[name]
+	return steps, ok
+}
+
+// IsMarkdown reports whether an installed doc's pages were pre-rendered to markdown.
+func (s *Store) IsMarkdown(slug string) bool {
+	meta, err := s.LoadMeta(slug)
+	return err == nil && slices.Contains(meta.Process, StepMarkdown)
+}
+
+// HTMLPath returns the local HTML file of an entry: the kept original of a
+// post-processed page, or the content file. It is "" for pages that are only
+// stored as markdown.
+func (s *Store) HTMLPath(slug, path string) string {
+	meta, err := s.LoadMeta(slug)
+	switch {
+	case err == nil && meta.Originals:
+		return s.originalPath(slug, path)
+	case err == nil && slices.Contains(meta.Process, StepMarkdown):
+		return ""
+	}
+	return s.ContentPath(slug, path)
+}
+
+// LoadHTML loads the HTML of an entry, preferring the kept original of
+// post-processed pages. Pages only stored as markdown return ErrNoHTML.
+func (s *Store) LoadHTML(slug, path string) (string, error) {
+	file := s.HTMLPath(slug, path)
+	if file == "" {
+		return "", ErrNoHTML
+	}
+	data, err := s.readDocFile(slug, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read content: %w", err)
+	}
+	return string(data), nil
+}
+
+// originalPath returns the kept original HTML file for an entry path.
+func (s *Store) originalPath(slug, path string) string {
+	page, _ := SplitPath(path)
+	return filepath.Join(s.DocDir(slug), originalsDir, page+".html")
+}
+
+// pageFiles returns the files stored for a content page of an installed doc.
+func (s *Store) pageFiles(meta *Meta, page string) []string {
+	files := []string{s.ContentPath(meta.Slug, page)}
+	if meta.Originals {
+		files = append(files, s.originalPath(meta.Slug, page))
+	}
+	return files
+}
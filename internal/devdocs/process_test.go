@@ -0,0 +1,103 @@
+package devdocs
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProcessPage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		steps   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "No steps", want: `<h1 style="color: red">Title</h1>  <p>Some   text</p>`},
+		{name: "Strip styles", steps: []string{StepStripStyles}, want: `<h1>Title</h1>  <p>Some   text</p>`},
+		{name: "Minify", steps: []string{StepStripStyles, StepMinify}, want: `<h1>Title</h1> <p>Some text</p>`},
+		{name: "Markdown", steps: []string{StepMarkdown}, want: "# Title\n\nSome text"},
+		{name: "Unknown step", steps: []string{"gzip"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := processPage(`<h1 style="color: red">Title</h1>  <p>Some   text</p>`, tt.steps)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("processPage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("processPage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostProcessInstall(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	db := map[string]string{"hooks": `<h1 id="use" style="color: red">useState</h1><p>Returns state.</p>`}
+	manifest := []Doc{{Slug: "react~18", Mtime: 1}}
+
+	// Steps configured by doc name apply to every version
+	store := NewStore(WithDataDir(dataDir), WithPostProcess(map[string][]string{"react": {StepStripStyles, StepMarkdown}}, true))
+	meta, err := store.Install("react~18", &Index{}, db, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(meta.Process, []string{StepStripStyles, StepMarkdown}) || !meta.Originals {
+		t.Errorf("meta = %+v, want process steps and originals", meta)
+	}
+	if !store.IsMarkdown("react~18") {
+		t.Error("IsMarkdown() = false")
+	}
+	if got, _ := store.LoadContent("react~18", "hooks"); got != "# useState\n\nReturns state." {
+		t.Errorf("LoadContent() = %q, want markdown", got)
+	}
+	if got, _ := store.LoadHTML("react~18", "hooks#use"); got != db["hooks"] {
+		t.Errorf("LoadHTML() = %q, want the original", got)
+	}
+	if result, err := store.Verify("react~18"); err != nil || !result.OK() {
+		t.Errorf("Verify() = %+v, %v", result, err)
+	}
+
+	// Upgrades without configuration keep the steps
+	manifest[0].Mtime = 2
+	meta, err = NewStore(WithDataDir(dataDir)).Install("react~18", &Index{}, db, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Process) != 2 || !meta.Originals {
+		t.Errorf("upgrade dropped post-processing: %+v", meta)
+	}
+
+	// Without originals, markdown pages have no HTML
+	store = NewStore(WithDataDir(dataDir), WithPostProcess(map[string][]string{"react~18": {StepMarkdown}}, false))
+	if _, err := store.Install("react~18", &Index{}, db, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.LoadHTML("react~18", "hooks"); !errors.Is(err, ErrNoHTML) {
+		t.Errorf("LoadHTML() error = %v, want ErrNoHTML", err)
+	}
+	if _, err := store.Bundle("react~18", false); !errors.Is(err, ErrNoHTML) {
+		t.Errorf("Bundle() error = %v, want ErrNoHTML", err)
+	}
+
+	// An empty list turns post-processing off
+	store = NewStore(WithDataDir(dataDir), WithPostProcess(map[string][]string{"react": {}}, false))
+	if meta, err = store.Install("react~18", &Index{}, db, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Process) > 0 || store.IsMarkdown("react~18") {
+		t.Errorf("meta.Process = %v, want none", meta.Process)
+	}
+	if got, _ := store.LoadContent("react~18", "hooks"); !strings.HasPrefix(got, "<h1") {
+		t.Errorf("LoadContent() = %q, want HTML", got)
+	}
+}
@@ -2,40 +2,194 @@
 package devdocs
 
 import (
+	"crypto/cipher"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
+// Where an installed doc came from (Meta.Source)
+const (
+	SourceDevDocs = "devdocs" // The DevDocs API
+	SourceBundle  = "bundle"  // A prebuilt bundle
+	SourceMirror  = "mirror"  // A bundle imported from a mirror directory
+)
+
 // Meta represents local metadata for an installed doc
 type Meta struct {
 	Slug      string    `json:"slug"`
+	Source    string    `json:"source"` // Where the doc was installed from (e.g., "devdocs")
 	Mtime     int64     `json:"mtime"`
 	Installed time.Time `json:"installed"`
 	DBSize    int64     `json:"db_size"`
+
+	Attribution string `json:"attribution,omitempty"` // Copyright and license notice from the catalog (HTML)
+
+	Encryption *Encryption `json:"encryption,omitempty"` // Set when the doc is encrypted at rest
+	Compressed bool        `json:"compressed,omitempty"` // Content pages are zstd-compressed
+	Pinned     bool        `json:"pinned,omitempty"`     // Kept at this release by upgrades unless forced
+	Only       []string    `json:"only,omitempty"`       // Partial install: entry type or path prefixes kept
+	Process    []string    `json:"process,omitempty"`    // Post-processing steps applied to content pages
+	Originals  bool        `json:"originals,omitempty"`  // Original HTML of processed pages kept under originals/
 }
 
 // Store handles downloading and storing DevDocs documentation
 type Store struct {
 	dataDir  string
 	cacheDir string
+
+	passphrase string // Unlocks encrypted docs
+	encrypt    bool   // Encrypt new installs at rest
+	compress   bool   // Compress the content of new installs
+
+	only []string // Only install entries with these type or path prefixes
+
+	process       map[string][]string // Post-processing steps of new installs, by slug or name
+	keepOriginals bool                // Keep the original HTML of processed pages
+
+	mu      sync.Mutex
+	ciphers map[string]cipher.AEAD // slug -> derived cipher, cached per process
+}
+
+// StoreOption allows configuring the store
+type StoreOption func(*Store)
+
+// WithDataDir sets the directory where docs are installed (docs/{slug}/)
+func WithDataDir(dir string) StoreOption {
+	return func(s *Store) {
+		s.dataDir = dir
+	}
+}
+
+// WithCacheDir sets the directory for caching the manifest (e.g., ~/.cache/dsearch)
+func WithCacheDir(dir string) StoreOption {
+	return func(s *Store) {
+		s.cacheDir = dir
+	}
+}
+
+// WithPassphrase sets the passphrase used to read encrypted docs
+func WithPassphrase(passphrase string) StoreOption {
+	return func(s *Store) {
+		s.passphrase = passphrase
+	}
+}
+
+// WithEncryption encrypts new installs at rest with a key derived from passphrase
+func WithEncryption(passphrase string) StoreOption {
+	return func(s *Store) {
+		s.passphrase = passphrase
+		s.encrypt = true
+	}
 }
 
-// NewStore creates a new Store with the given root directory.
-// cacheDir is the directory for caching the manifest (e.g., ~/.cache/dsearch)
-func NewStore(rootDir, cacheDir string) *Store {
-	return &Store{
-		dataDir:  rootDir,
-		cacheDir: cacheDir,
+// WithCompression stores the content of new installs zstd-compressed
+func WithCompression(enabled bool) StoreOption {
+	return func(s *Store) {
+		s.compress = enabled
+	}
+}
+
+// WithOnly installs only the entries whose type or path starts with one of
+// the filters (see FilterIndex), instead of whole docs
+func WithOnly(filters []string) StoreOption {
+	return func(s *Store) {
+		s.only = filters
+	}
+}
+
+// WithPostProcess runs the content pages of new installs through
+// post-processing steps (see PostProcess), configured by doc slug or by doc
+// name for all its versions. With keepOriginals, the original HTML is kept
+// alongside for features that need it.
+func WithPostProcess(steps map[string][]string, keepOriginals bool) StoreOption {
+	return func(s *Store) {
+		s.process = steps
+		s.keepOriginals = keepOriginals
+	}
+}
+
+// NewStore creates a new Store.
+// If no cache directory is given, the manifest is cached in the data directory.
+func NewStore(opts ...StoreOption) *Store {
+	s := &Store{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.cacheDir == "" {
+		s.cacheDir = s.dataDir
+	}
+
+	return s
+}
+
+// ContentSource feeds every page of a doc to fn, e.g. while streaming db.json
+type ContentSource func(fn ContentFunc) error
+
+// MapSource returns a ContentSource over an already decoded db.json.
+func MapSource(db map[string]string) ContentSource {
+	return func(fn ContentFunc) error {
+		for path, content := range db {
+			if err := fn(path, content); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// InstallOption describes the content of a single install
+type InstallOption func(*installConfig)
+
+type installConfig struct {
+	source  string   // Meta.Source, SourceDevDocs by default
+	only    []string // Entry type or path prefixes the content was already narrowed to
+	process []string // Post-processing steps already applied to the content
+}
+
+// WithSource records where the installed content comes from.
+func WithSource(source string) InstallOption {
+	return func(c *installConfig) {
+		c.source = source
 	}
 }
 
 // Install downloads and installs a documentation set
 // Returns the local metadata for the installed doc
-func (s *Store) Install(slug string, index *Index, db map[string]string, manifest []Doc) (*Meta, error) {
+func (s *Store) Install(slug string, index *Index, db map[string]string, manifest []Doc, opts ...InstallOption) (*Meta, error) {
+	return s.InstallFrom(slug, index, MapSource(db), manifest, opts...)
+}
+
+// ValidateSlug checks that a doc slug is safe to use as a directory name
+// below the data directory. Slugs from catalogs, bundles and mirrors are not
+// trusted, so they must not be empty, absolute, hidden or contain path
+// separators or "..".
+func ValidateSlug(slug string) error {
+	if slug == "" || strings.HasPrefix(slug, ".") || strings.Contains(slug, "..") ||
+		strings.ContainsAny(slug, `/\`) || filepath.IsAbs(slug) || filepath.VolumeName(slug) != "" {
+		return fmt.Errorf("invalid doc slug %q", slug)
+	}
+	return nil
+}
+
+// InstallFrom installs a documentation set, writing each page to disk as the
+// source produces it so the whole db.json never has to be held in memory.
+func (s *Store) InstallFrom(slug string, index *Index, source ContentSource, manifest []Doc, opts ...InstallOption) (*Meta, error) {
+	if err := ValidateSlug(slug); err != nil {
+		return nil, err
+	}
+	cfg := installConfig{source: SourceDevDocs}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	// Find doc in manifest to get mtime and db_size
 	var docInfo *Doc
 	for i := range manifest {
@@ -48,15 +202,104 @@ func (s *Store) Install(slug string, index *Index, db map[string]string, manifes
 		return nil, fmt.Errorf("doc %s not found in manifest", slug)
 	}
 
-	// Create doc directory
-	docDir := filepath.Join(s.dataDir, "docs", slug)
-	if err := os.MkdirAll(docDir, 0755); err != nil {
+	// Concurrent installs of the same doc would swap in each other's staging
+	lock, err := s.lockDoc(slug)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	// Compressed docs stay compressed, pinned docs stay pinned and partial
+	// installs keep their filters on reinstall, unless the content itself is
+	// partial
+	var compressed, pinned bool
+	only := s.only
+	if len(only) == 0 {
+		only = cfg.only
+	}
+	prev, err := s.LoadMeta(slug)
+	if err == nil {
+		compressed, pinned = prev.Compressed, prev.Pinned
+		if len(only) == 0 {
+			only = prev.Only
+		}
+	}
+	compressed = compressed || s.compress
+
+	// Post-processing follows the configuration, or the previous install for
+	// docs that aren't configured (e.g. upgrades)
+	process, configured := s.processSteps(slug)
+	keepOriginals := s.keepOriginals
+	if !configured && prev != nil {
+		process, keepOriginals = prev.Process, prev.Originals
+	}
+	// Content processed before it was bundled only gets the remaining steps,
+	// and its original HTML is gone
+	apply := process
+	if len(cfg.process) > 0 {
+		apply = slices.DeleteFunc(slices.Clone(process), func(step string) bool {
+			return slices.Contains(cfg.process, step)
+		})
+		process = append(slices.Clone(cfg.process), apply...)
+		keepOriginals = false
+	}
+	keepOriginals = keepOriginals && len(process) > 0
+
+	// Updates carry over the files of pages that didn't change
+	unchanged := s.deltaPages(prev, docInfo.Mtime, compressed)
+
+	// Encrypt when requested, and keep encrypted docs encrypted on reinstall.
+	// Updates keep the key, so unchanged pages stay readable.
+	var enc *Encryption
+	var aead cipher.AEAD
+	if s.encrypt || s.IsEncrypted(slug) {
+		if s.passphrase == "" {
+			return nil, fmt.Errorf("%s: %w", slug, ErrPassphraseRequired)
+		}
+		if unchanged != nil && prev.Encryption != nil {
+			if aead, err = s.cipherFor(slug); err == nil {
+				enc = prev.Encryption
+			}
+		}
+		if enc == nil {
+			if enc, aead, err = newEncryption(s.passphrase); err != nil {
+				return nil, err
+			}
+			unchanged = nil
+		}
+	}
+
+	if len(only) > 0 {
+		index = FilterIndex(index, only)
+		if len(index.Entries) == 0 {
+			return nil, fmt.Errorf("no entries of %s match %s", slug, strings.Join(only, ", "))
+		}
+		source = filterSource(source, index)
+	}
+
+	// Write the doc to a staging directory next to its final location, so a
+	// failed install leaves the previous version (or nothing) behind
+	docsDir := filepath.Join(s.dataDir, "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create doc directory: %w", err)
+	}
+	s.removeStaging(slug)
+	docDir, err := os.MkdirTemp(docsDir, stagingPrefix(slug))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create doc directory: %w", err)
+	}
+	defer os.RemoveAll(docDir)
+	if err := os.Chmod(docDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create doc directory: %w", err)
 	}
 
 	// Save index.json
 	indexPath := filepath.Join(docDir, "index.json")
-	if err := writeJSON(indexPath, index); err != nil {
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal index: %w", err)
+	}
+	if err := writeDocFile(indexPath, indexData, aead); err != nil {
 		return nil, fmt.Errorf("failed to save index: %w", err)
 	}
 
@@ -66,40 +309,138 @@ func (s *Store) Install(slug string, index *Index, db map[string]string, manifes
 		return nil, fmt.Errorf("failed to create content directory: %w", err)
 	}
 
-	for path, content := range db {
+	installedContent := filepath.Join(s.DocDir(slug), "content")
+	originals := filepath.Join(docDir, originalsDir)
+	checksums := make(map[string]string)
+	err = source(func(path, content string) error {
 		// Ensure path is safe (no directory traversal)
 		if filepath.IsAbs(path) || strings.Contains(path, "..") {
-			continue
+			return nil
+		}
+		if len(apply) > 0 {
+			if keepOriginals {
+				if err := writePage(originals, path, content, compressed, aead); err != nil {
+					return err
+				}
+			}
+			var err error
+			if content, err = processPage(content, apply); err != nil {
+				return fmt.Errorf("failed to process %s: %w", path, err)
+			}
 		}
-		contentFile := filepath.Join(contentDir, path+".html")
-		contentDirPath := filepath.Dir(contentFile)
-		if err := os.MkdirAll(contentDirPath, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create content subdir: %w", err)
+
+		sum := pageChecksum(content)
+		if unchanged[path] == sum && linkPage(installedContent, contentDir, path) == nil {
+			checksums[path] = sum
+			return nil
 		}
-		if err := os.WriteFile(contentFile, []byte(content), 0644); err != nil {
-			return nil, fmt.Errorf("failed to write content file: %w", err)
+		if err := writePage(contentDir, path, content, compressed, aead); err != nil {
+			return err
 		}
+		checksums[path] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Save checksums.json for verify
+	if err := writeChecksums(docDir, checksums, aead); err != nil {
+		return nil, fmt.Errorf("failed to save checksums: %w", err)
 	}
 
 	// Create and save meta.json
 	meta := &Meta{
 		Slug:      slug,
+		Source:    cfg.source,
 		Mtime:     docInfo.Mtime,
 		Installed: time.Now(),
 		DBSize:    docInfo.DBSize,
+
+		Attribution: docInfo.Attribution,
+
+		Encryption: enc,
+		Compressed: compressed,
+		Pinned:     pinned,
+		Only:       only,
+		Process:    process,
+		Originals:  keepOriginals,
 	}
 	metaPath := filepath.Join(docDir, "meta.json")
 	if err := writeJSON(metaPath, meta); err != nil {
 		return nil, fmt.Errorf("failed to save meta: %w", err)
 	}
 
+	if err := s.commitStaging(slug, docDir); err != nil {
+		return nil, err
+	}
+	s.setCipher(slug, aead)
+
 	return meta, nil
 }
 
+// stagingPrefix names the staging directories of a doc. The leading dot keeps
+// them out of ListInstalled.
+func stagingPrefix(slug string) string {
+	return "." + slug + ".staging-"
+}
+
+// removeStaging removes staging directories left behind by interrupted installs of slug.
+func (s *Store) removeStaging(slug string) {
+	leftovers, _ := filepath.Glob(filepath.Join(s.dataDir, "docs", stagingPrefix(slug)+"*"))
+	for _, dir := range leftovers {
+		os.RemoveAll(dir)
+	}
+}
+
+// commitStaging replaces the installed doc with the one in stagingDir. The
+// previous version is moved aside first and restored if the swap fails.
+func (s *Store) commitStaging(slug, stagingDir string) error {
+	docDir := s.DocDir(slug)
+	backup := stagingDir + ".old"
+	hadPrevious := false
+	if err := os.Rename(docDir, backup); err == nil {
+		hadPrevious = true
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace %s: %w", slug, err)
+	}
+
+	if err := os.Rename(stagingDir, docDir); err != nil {
+		if hadPrevious {
+			os.Rename(backup, docDir)
+		}
+		return fmt.Errorf("failed to replace %s: %w", slug, err)
+	}
+	if hadPrevious {
+		os.RemoveAll(backup)
+	}
+	return nil
+}
+
+// writePage writes one content page below contentDir, compressing and
+// encrypting it as requested.
+func writePage(contentDir, path, content string, compressed bool, aead cipher.AEAD) error {
+	contentFile := filepath.Join(contentDir, path+".html")
+	if err := os.MkdirAll(filepath.Dir(contentFile), 0755); err != nil {
+		return fmt.Errorf("failed to create content subdir: %w", err)
+	}
+	data := []byte(content)
+	if compressed {
+		var err error
+		if data, err = compress(data); err != nil {
+			return fmt.Errorf("failed to compress content: %w", err)
+		}
+	}
+	if err := writeDocFile(contentFile, data, aead); err != nil {
+		return fmt.Errorf("failed to write content file: %w", err)
+	}
+	return nil
+}
+
 // LoadIndex loads the search index for an installed doc
 func (s *Store) LoadIndex(slug string) (*Index, error) {
 	indexPath := filepath.Join(s.dataDir, "docs", slug, "index.json")
-	data, err := os.ReadFile(indexPath)
+	data, err := s.readDocFile(slug, indexPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read index: %w", err)
 	}
@@ -112,10 +453,84 @@ func (s *Store) LoadIndex(slug string) (*Index, error) {
 	return &index, nil
 }
 
+// LoadMeta loads the local metadata for an installed doc
+func (s *Store) LoadMeta(slug string) (*Meta, error) {
+	metaPath := filepath.Join(s.DocDir(slug), "meta.json")
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read meta: %w", err)
+	}
+
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal meta: %w", err)
+	}
+
+	// Docs installed before sources were recorded all came from DevDocs
+	if meta.Source == "" {
+		meta.Source = SourceDevDocs
+	}
+
+	return &meta, nil
+}
+
+// Attribution returns the copyright and license notice (HTML) of an installed
+// doc. Docs installed before notices were recorded fall back to the cached
+// manifest; the result is empty when neither has one.
+func (s *Store) Attribution(slug string) string {
+	if meta, err := s.LoadMeta(slug); err == nil && meta.Attribution != "" {
+		return meta.Attribution
+	}
+	manifest, _ := s.LoadManifest()
+	for _, doc := range manifest {
+		if doc.Slug == slug {
+			return doc.Attribution
+		}
+	}
+	return ""
+}
+
+// SetPinned pins or unpins an installed doc. Pinned docs are skipped by
+// upgrades and reinstalls unless forced.
+func (s *Store) SetPinned(slug string, pinned bool) error {
+	lock, err := s.lockDoc(slug)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	meta, err := s.LoadMeta(slug)
+	if err != nil {
+		return err
+	}
+	meta.Pinned = pinned
+	if err := writeJSON(filepath.Join(s.DocDir(slug), "meta.json"), meta); err != nil {
+		return fmt.Errorf("failed to save meta: %w", err)
+	}
+	return nil
+}
+
+// IsPinned reports whether an installed doc is pinned.
+func (s *Store) IsPinned(slug string) bool {
+	meta, err := s.LoadMeta(slug)
+	return err == nil && meta.Pinned
+}
+
+// DocDir returns the on-disk directory of a doc
+func (s *Store) DocDir(slug string) string {
+	return filepath.Join(s.dataDir, "docs", slug)
+}
+
+// ContentPath returns the local HTML file for an entry path.
+// Any #anchor in the path is dropped since anchors live inside the page.
+func (s *Store) ContentPath(slug, path string) string {
+	page, _ := SplitPath(path)
+	return filepath.Join(s.DocDir(slug), "content", page+".html")
+}
+
 // LoadContent loads HTML content for a specific path in an installed doc
 func (s *Store) LoadContent(slug, path string) (string, error) {
-	contentPath := filepath.Join(s.dataDir, "docs", slug, "content", path+".html")
-	data, err := os.ReadFile(contentPath)
+	data, err := s.readDocFile(slug, s.ContentPath(slug, path))
 	if err != nil {
 		return "", fmt.Errorf("failed to read content: %w", err)
 	}
@@ -123,6 +538,20 @@ func (s *Store) LoadContent(slug, path string) (string, error) {
 	return string(data), nil
 }
 
+// ListPages returns the content page paths of an installed doc (without .html)
+func (s *Store) ListPages(slug string) ([]string, error) {
+	var pages []string
+	err := s.walkPages(slug, func(page, _ string) error {
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
 // IsInstalled checks if a doc is installed
 func (s *Store) IsInstalled(slug string) bool {
 	docDir := filepath.Join(s.dataDir, "docs", slug)
@@ -143,7 +572,8 @@ func (s *Store) ListInstalled() []string {
 
 	var slugs []string
 	for _, entry := range entries {
-		if entry.IsDir() {
+		// Dot directories are in-progress installs
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
 			slugs = append(slugs, entry.Name())
 		}
 	}
@@ -153,9 +583,14 @@ func (s *Store) ListInstalled() []string {
 
 // SaveManifest saves the DevDocs manifest to cache
 func (s *Store) SaveManifest(manifest []Doc) error {
-	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
+	lock, err := s.lockManifest()
+	if err != nil {
+		return err
 	}
+	defer lock.release()
+
+	// Validators of a previous manifest no longer apply
+	_ = os.Remove(filepath.Join(s.cacheDir, manifestValidatorsFile))
 
 	manifestPath := filepath.Join(s.cacheDir, "manifest.json")
 	return writeJSON(manifestPath, manifest)
@@ -179,8 +614,32 @@ func (s *Store) LoadManifest() ([]Doc, error) {
 
 // Uninstall removes an installed doc
 func (s *Store) Uninstall(slug string) error {
+	if err := ValidateSlug(slug); err != nil {
+		return err
+	}
+	lock, err := s.lockDoc(slug)
+	if err != nil {
+		return err
+	}
 	docDir := filepath.Join(s.dataDir, "docs", slug)
-	return os.RemoveAll(docDir)
+	s.setCipher(slug, nil)
+	err = os.RemoveAll(docDir)
+	lock.release()
+	if err != nil {
+		return err
+	}
+
+	usageLock, err := s.lockUsage()
+	if err != nil {
+		return err
+	}
+	defer usageLock.release()
+	usage := s.loadUsage()
+	if _, ok := usage[slug]; ok {
+		delete(usage, slug)
+		return writeJSON(filepath.Join(s.dataDir, usageFile), usage)
+	}
+	return nil
 }
 
 // writeJSON is a helper to write JSON to a file
@@ -189,5 +648,5 @@ func writeJSON(path string, v any) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data)
 }
@@ -2,8 +2,11 @@
 package devdocs
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -27,7 +30,7 @@ func TestInstallNewDoc(t *testing.T) {
 		{Name: "Test", Slug: "test", Mtime: 12345, DBSize: 100},
 	}
 
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	// Install
 	meta, err := store.Install("test", mockIndex, mockDB, mockManifest)
@@ -81,7 +84,7 @@ func TestLoadIndex(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	// Load index
 	index, err := store.LoadIndex("test")
@@ -113,7 +116,7 @@ func TestLoadContent(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	// Load content
 	content, err := store.LoadContent("test", "test/path")
@@ -124,6 +127,15 @@ func TestLoadContent(t *testing.T) {
 	if content != expectedContent {
 		t.Errorf("Content = %q, want %q", content, expectedContent)
 	}
+
+	// Anchors point inside the same page
+	content, err = store.LoadContent("test", "test/path#section")
+	if err != nil {
+		t.Fatalf("LoadContent() with anchor error = %v", err)
+	}
+	if content != expectedContent {
+		t.Errorf("Content with anchor = %q, want %q", content, expectedContent)
+	}
 }
 
 func TestIsInstalled(t *testing.T) {
@@ -135,7 +147,7 @@ func TestIsInstalled(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	// Test installed
 	if !store.IsInstalled("test") {
@@ -159,7 +171,7 @@ func TestListInstalled(t *testing.T) {
 		}
 	}
 
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	// List
 	installed := store.ListInstalled()
@@ -175,7 +187,7 @@ func TestSaveAndLoadManifest(t *testing.T) {
 		{Name: "Test", Slug: "test", Mtime: 12345},
 	}
 
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	// Save
 	err := store.SaveManifest(mockManifest)
@@ -208,7 +220,7 @@ func TestLoadManifest_NotExists(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Use same directory for both data and cache in tests
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	_, err := store.LoadManifest()
 	if err == nil {
@@ -225,7 +237,7 @@ func TestUninstall(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	store := NewStore(tmpDir, tmpDir)
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
 
 	// Uninstall
 	err := store.Uninstall("test")
@@ -238,3 +250,236 @@ func TestUninstall(t *testing.T) {
 		t.Error("Expected test to be uninstalled")
 	}
 }
+
+func TestInstall_InvalidSlug(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := NewStore(WithDataDir(filepath.Join(root, "data")))
+	if _, err := store.Install("../../x", &Index{}, map[string]string{"index": "<p>x</p>"}, nil); err == nil {
+		t.Fatal("Install() expected error for a slug outside the docs directory")
+	}
+	if _, err := os.Stat(filepath.Join(root, "x")); !os.IsNotExist(err) {
+		t.Errorf("Install() wrote outside the docs directory: %v", err)
+	}
+}
+
+func TestInstallSource(t *testing.T) {
+	t.Parallel()
+
+	bundle := &Bundle{Slug: "go", Index: &Index{}, DB: map[string]string{}}
+	tests := []struct {
+		name string
+		opts []InstallOption
+		want string
+	}{
+		{name: "DevDocs", want: SourceDevDocs},
+		{name: "Bundle", opts: []InstallOption{WithBundle(bundle)}, want: SourceBundle},
+		{name: "Mirror", opts: []InstallOption{WithBundle(bundle), WithSource(SourceMirror)}, want: SourceMirror},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewStore(WithDataDir(t.TempDir()))
+			if _, err := store.Install("go", &Index{}, nil, []Doc{{Slug: "go"}}, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			meta, err := store.LoadMeta("go")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta.Source != tt.want {
+				t.Errorf("Source = %q, want %q", meta.Source, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStore_CacheDirDefaultsToDataDir(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	store := NewStore(WithDataDir(tmpDir))
+
+	if err := store.SaveManifest([]Doc{{Name: "Test", Slug: "test"}}); err != nil {
+		t.Fatalf("SaveManifest() error = %v", err)
+	}
+
+	manifestPath := filepath.Join(tmpDir, "manifest.json")
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		t.Errorf("manifest.json not found at %s", manifestPath)
+	}
+}
+
+func TestListPages(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	store := NewStore(WithDataDir(tmpDir), WithCacheDir(tmpDir))
+	mockDB := map[string]string{
+		"index":           "<h1>Index</h1>",
+		"net/http/index":  "<h1>net/http</h1>",
+		"net/http/client": "<h1>Client</h1>",
+	}
+	if _, err := store.Install("test", &Index{}, mockDB, []Doc{{Slug: "test"}}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	pages, err := store.ListPages("test")
+	if err != nil {
+		t.Fatalf("ListPages() error = %v", err)
+	}
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d: %v", len(pages), pages)
+	}
+	for _, page := range pages {
+		if _, ok := mockDB[page]; !ok {
+			t.Errorf("Unexpected page %q", page)
+		}
+	}
+}
+
+func TestSetPinned(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	manifest := []Doc{{Slug: "react~18", Mtime: 1}}
+	if _, err := store.Install("react~18", &Index{}, nil, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetPinned("react~18", true); err != nil {
+		t.Fatalf("SetPinned() error = %v", err)
+	}
+	if !store.IsPinned("react~18") {
+		t.Error("IsPinned() = false after SetPinned(true)")
+	}
+
+	// A forced reinstall keeps the pin
+	manifest[0].Mtime = 2
+	meta, err := store.Install("react~18", &Index{}, nil, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !meta.Pinned {
+		t.Error("reinstall dropped the pin")
+	}
+
+	if err := store.SetPinned("react~18", false); err != nil {
+		t.Fatalf("SetPinned() error = %v", err)
+	}
+	if store.IsPinned("react~18") {
+		t.Error("IsPinned() = true after SetPinned(false)")
+	}
+
+	if err := store.SetPinned("vue", true); err == nil {
+		t.Error("SetPinned() of a doc that isn't installed should fail")
+	}
+}
+
+func TestAttribution(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	notice := "&copy; Meta Platforms, Inc.<br>Licensed under the MIT License."
+	manifest := []Doc{{Slug: "react~18", Mtime: 1, Attribution: notice}, {Slug: "go", Mtime: 1}}
+
+	meta, err := store.Install("react~18", &Index{}, nil, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Attribution != notice {
+		t.Errorf("meta.Attribution = %q, want %q", meta.Attribution, notice)
+	}
+	if got := store.Attribution("react~18"); got != notice {
+		t.Errorf("Attribution() = %q, want %q", got, notice)
+	}
+
+	// Docs installed without a notice fall back to the cached manifest
+	if _, err := store.Install("go", &Index{}, nil, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Attribution("go"); got != "" {
+		t.Errorf("Attribution() without notice = %q, want empty", got)
+	}
+	manifest[1].Attribution = "&copy; Google, Inc."
+	if err := store.SaveManifest(manifest); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Attribution("go"); got != manifest[1].Attribution {
+		t.Errorf("Attribution() from manifest = %q, want %q", got, manifest[1].Attribution)
+	}
+}
+
+func TestInstallRollsBackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	store := NewStore(WithDataDir(dataDir))
+	manifest := []Doc{{Slug: "go", Mtime: 1}}
+	index := &Index{Entries: []Entry{{Name: "fmt", Path: "fmt", Type: "Package"}}}
+
+	failing := func(fn ContentFunc) error {
+		if err := fn("fmt", "<p>new fmt</p>"); err != nil {
+			return err
+		}
+		return errors.New("connection reset")
+	}
+
+	// A failed first install leaves nothing behind
+	if _, err := store.InstallFrom("go", index, failing, manifest); err == nil {
+		t.Fatal("InstallFrom() with a failing source should fail")
+	}
+	if store.IsInstalled("go") {
+		t.Error("IsInstalled() = true after a failed install")
+	}
+	assertNoStaging(t, dataDir)
+
+	if _, err := store.Install("go", index, map[string]string{"fmt": "<p>fmt</p>", "os": "<p>os</p>"}, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed reinstall keeps the previous version intact
+	manifest[0].Mtime = 2
+	if _, err := store.InstallFrom("go", index, failing, manifest); err == nil {
+		t.Fatal("InstallFrom() with a failing source should fail")
+	}
+	meta, err := store.LoadMeta("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Mtime != 1 {
+		t.Errorf("LoadMeta() Mtime = %d, want the previous install's 1", meta.Mtime)
+	}
+	if content, _ := store.LoadContent("go", "fmt"); content != "<p>fmt</p>" {
+		t.Errorf("LoadContent() = %q, want the previous content", content)
+	}
+	assertNoStaging(t, dataDir)
+
+	// A successful reinstall replaces the doc, dropping pages that are gone
+	if _, err := store.Install("go", index, map[string]string{"fmt": "<p>new fmt</p>"}, manifest); err != nil {
+		t.Fatal(err)
+	}
+	pages, err := store.ListPages("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pages, []string{"fmt"}) {
+		t.Errorf("ListPages() after reinstall = %v, want [fmt]", pages)
+	}
+	assertNoStaging(t, dataDir)
+}
+
+// assertNoStaging fails when staging directories are left in the docs directory.
+func assertNoStaging(t *testing.T, dataDir string) {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(dataDir, "docs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".staging-") {
+			t.Errorf("staging directory %s left behind", entry.Name())
+		}
+	}
+}
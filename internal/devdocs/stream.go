@@ -0,0 +1,97 @@
+package devdocs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// EntryReader opens the content page of an entry for reading, decompressing
+// and decrypting it as needed. Plain and compressed pages are streamed from
+// disk; encrypted pages can only be authenticated as a whole, so they are
+// decrypted into memory first. Any #anchor in the path is dropped.
+func (s *Store) EntryReader(slug, path string) (io.ReadCloser, error) {
+	return s.openDocFile(slug, s.ContentPath(slug, path))
+}
+
+// WalkContent calls fn for every content page of an installed doc, in path
+// order, with a reader for the page that is closed when fn returns. It stops
+// at the first error, which is returned.
+func (s *Store) WalkContent(slug string, fn func(path string, r io.Reader) error) error {
+	return s.walkPages(slug, func(page, file string) error {
+		r, err := s.openDocFile(slug, file)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return fn(page, r)
+	})
+}
+
+// walkPages calls fn with the path and file of every content page of an
+// installed doc, in path order.
+func (s *Store) walkPages(slug string, fn func(page, file string) error) error {
+	contentDir := filepath.Join(s.DocDir(slug), "content")
+	return filepath.WalkDir(contentDir, func(file string, d os.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to list content: %w", err)
+		}
+		if d.IsDir() || !strings.HasSuffix(file, ".html") {
+			return nil
+		}
+		rel, err := filepath.Rel(contentDir, file)
+		if err != nil {
+			return fmt.Errorf("failed to list content: %w", err)
+		}
+		return fn(strings.TrimSuffix(filepath.ToSlash(rel), ".html"), file)
+	})
+}
+
+// openDocFile opens a file of an installed doc for reading, like readDocFile.
+func (s *Store) openDocFile(slug, file string) (io.ReadCloser, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(max(len(encryptedMagic), len(zstdMagic)))
+	switch {
+	case isEncrypted(head):
+		f.Close()
+		data, err := s.readDocFile(slug, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read content: %w", err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	case isCompressed(head):
+		dec, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		return &pageReader{Reader: dec, file: f, dec: dec}, nil
+	}
+	return &pageReader{Reader: br, file: f}, nil
+}
+
+// pageReader reads a content page from disk and closes its file (and
+// decoder) when done.
+type pageReader struct {
+	io.Reader
+	file *os.File
+	dec  *zstd.Decoder
+}
+
+func (r *pageReader) Close() error {
+	if r.dec != nil {
+		r.dec.Close()
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,101 @@
+package devdocs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEntryReader(t *testing.T) {
+	t.Parallel()
+
+	// Long enough to be stored compressed
+	db := map[string]string{
+		"fmt":       "<p>fmt</p>",
+		"net/http":  "<h1>net/http</h1>" + strings.Repeat("<p>Package http provides HTTP client and server implementations.</p>", 50),
+		"os/signal": "<p>signal</p>",
+	}
+
+	tests := []struct {
+		name string
+		opts []StoreOption
+	}{
+		{name: "Plain"},
+		{name: "Compressed", opts: []StoreOption{WithCompression(true)}},
+		{name: "Encrypted", opts: []StoreOption{WithEncryption("secret"), WithCompression(true)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewStore(append([]StoreOption{WithDataDir(t.TempDir())}, tt.opts...)...)
+			if _, err := store.Install("go", &Index{}, db, []Doc{{Slug: "go", Mtime: 1}}); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := store.EntryReader("go", "net/http#Client")
+			if err != nil {
+				t.Fatalf("EntryReader() error = %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("reading entry: %v", err)
+			}
+			if err := r.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			if string(got) != db["net/http"] {
+				t.Errorf("EntryReader() read %d bytes, want %d", len(got), len(db["net/http"]))
+			}
+
+			var paths []string
+			err = store.WalkContent("go", func(path string, r io.Reader) error {
+				content, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				if string(content) != db[path] {
+					t.Errorf("WalkContent() content of %s = %q", path, content)
+				}
+				paths = append(paths, path)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WalkContent() error = %v", err)
+			}
+			if want := []string{"fmt", "net/http", "os/signal"}; !reflect.DeepEqual(paths, want) {
+				t.Errorf("WalkContent() paths = %v, want %v", paths, want)
+			}
+		})
+	}
+}
+
+func TestWalkContentStops(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	if _, err := store.Install("go", &Index{}, map[string]string{"a": "a", "b": "b"}, []Doc{{Slug: "go", Mtime: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := store.WalkContent("go", func(path string, r io.Reader) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Errorf("WalkContent() = %v after %d call(s), want errStop after 1", err, calls)
+	}
+
+	if _, err := store.EntryReader("go", "missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("EntryReader() of a missing page error = %v, want ErrNotExist", err)
+	}
+	if err := store.WalkContent("vue", func(string, io.Reader) error { return nil }); err == nil {
+		t.Error("WalkContent() of a doc that isn't installed should fail")
+	}
+}
@@ -0,0 +1,94 @@
+package devdocs
+
+import (
+	"sort"
+	"strings"
+)
+
+// SuggestDocs returns up to n docs from manifest whose slug, name, alias or
+// type is close to input, for "did you mean" hints when a slug is unknown.
+// Without a version in input, only the first doc of each family (e.g. the
+// latest python~3.x) is suggested.
+func SuggestDocs(manifest []Doc, input string, n int) []Doc {
+	query, version, _ := strings.Cut(strings.ToLower(input), "~")
+	if query == "" {
+		return nil
+	}
+	maxDist := max(2, len(query)/2)
+
+	type candidate struct {
+		doc   Doc
+		score int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	for _, doc := range manifest {
+		family, _, _ := strings.Cut(doc.Slug, "~")
+		key := doc.Slug
+		if version == "" {
+			key = family
+		}
+		if seen[key] {
+			continue
+		}
+
+		score := -1
+		for _, name := range []string{family, doc.Name, doc.Alias, doc.Type} {
+			if d := nameDistance(query, strings.ToLower(name)); d <= maxDist && (score < 0 || d < score) {
+				score = d
+			}
+		}
+		if score < 0 {
+			continue
+		}
+		seen[key] = true
+		candidates = append(candidates, candidate{doc: doc, score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score < candidates[j].score
+	})
+	var docs []Doc
+	for _, c := range candidates {
+		if len(docs) == n {
+			break
+		}
+		docs = append(docs, c.doc)
+	}
+	return docs
+}
+
+// nameDistance scores how close name is to query: their edit distance, but at
+// most 2 when one contains the other (e.g. "reactjs" and "react").
+func nameDistance(query, name string) int {
+	switch {
+	case name == "":
+		return len(query) + 1
+	case name == query:
+		return 0
+	case len(query) >= 3 && len(name) >= 3 && (strings.Contains(name, query) || strings.Contains(query, name)):
+		return min(2, levenshtein(query, name))
+	}
+	return levenshtein(query, name)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,68 @@
+package devdocs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestDocs(t *testing.T) {
+	t.Parallel()
+
+	manifest := []Doc{
+		{Name: "React", Slug: "react", Type: "react"},
+		{Name: "React", Slug: "react~17", Type: "react"},
+		{Name: "React Native", Slug: "react_native", Type: "react_native"},
+		{Name: "Preact", Slug: "preact", Type: "simple"},
+		{Name: "Angular", Slug: "angular", Type: "angular", Alias: "ng"},
+		{Name: "Python", Slug: "python~3.12", Type: "python"},
+		{Name: "Python", Slug: "python~3.11", Type: "python"},
+		{Name: "Go", Slug: "go", Type: "go"},
+		{Name: "PostgreSQL", Slug: "postgresql~16", Type: "postgres", Alias: "PSQL"},
+	}
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "reactjs", want: []string{"react", "preact"}},
+		{input: "ng", want: []string{"angular", "go"}},
+		{input: "pyhton", want: []string{"python~3.12"}},
+		{input: "python~3.99", want: []string{"python~3.12", "python~3.11"}},
+		{input: "react-native", want: []string{"react_native", "react"}},
+		{input: "psql", want: []string{"postgresql~16"}},
+		{input: "zzzzzz", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, doc := range SuggestDocs(manifest, tt.input, 3) {
+				got = append(got, doc.Slug)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestDocs(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"go", "", 2},
+		{"kitten", "sitting", 3},
+		{"react", "react", 0},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
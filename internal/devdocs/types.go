@@ -1,6 +1,8 @@
 // Package devdocs provides types and client for interacting with the DevDocs API
 package devdocs
 
+import "sort"
+
 // Doc represents a documentation entry from docs.json manifest
 type Doc struct {
 	Name        string `json:"name"`        // Display name (e.g., "Angular", "React")
@@ -33,3 +35,29 @@ type Type struct {
 	Count int    `json:"count"` // Number of entries in this category
 	Slug  string `json:"slug"`  // URL-safe category name
 }
+
+// TypeCounts returns the entry categories sorted by count (descending).
+// Falls back to counting entries when the index has no types list.
+func (idx *Index) TypeCounts() []Type {
+	types := make([]Type, 0, len(idx.Types))
+	if len(idx.Types) > 0 {
+		types = append(types, idx.Types...)
+	} else {
+		counts := make(map[string]int)
+		for _, entry := range idx.Entries {
+			counts[entry.Type]++
+		}
+		for name, count := range counts {
+			types = append(types, Type{Name: name, Count: count})
+		}
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Count != types[j].Count {
+			return types[i].Count > types[j].Count
+		}
+		return types[i].Name < types[j].Name
+	})
+
+	return types
+}
@@ -131,3 +131,41 @@ func TestTypeUnmarshal(t *testing.T) {
 		t.Errorf("Slug = %q, want hooks", docType.Slug)
 	}
 }
+
+func TestIndexTypeCounts(t *testing.T) {
+	t.Run("uses types list", func(t *testing.T) {
+		index := Index{
+			Types: []Type{
+				{Name: "Classes", Count: 2},
+				{Name: "Functions", Count: 5},
+				{Name: "Constants", Count: 2},
+			},
+		}
+
+		types := index.TypeCounts()
+		want := []string{"Functions", "Classes", "Constants"}
+		for i, name := range want {
+			if types[i].Name != name {
+				t.Errorf("types[%d] = %q, want %q", i, types[i].Name, name)
+			}
+		}
+	})
+
+	t.Run("counts entries without types list", func(t *testing.T) {
+		index := Index{
+			Entries: []Entry{
+				{Name: "a", Type: "Hooks"},
+				{Name: "b", Type: "Hooks"},
+				{Name: "c", Type: "APIs"},
+			},
+		}
+
+		types := index.TypeCounts()
+		if len(types) != 2 {
+			t.Fatalf("Expected 2 types, got %d", len(types))
+		}
+		if types[0].Name != "Hooks" || types[0].Count != 2 {
+			t.Errorf("types[0] = %+v, want Hooks: 2", types[0])
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package devdocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// usageFile records when each doc was last used (slug -> time), for LRU pruning.
+const usageFile = "usage.json"
+
+// DocUsage describes an installed doc's disk usage and when it was last used.
+type DocUsage struct {
+	Slug     string
+	Size     int64     // Bytes on disk
+	LastUsed time.Time // Last search or read hit, or the install time if never used
+	Pinned   bool
+
+	links map[fileID]int64 // Size of files with other hard links, counted once across docs
+}
+
+// fileID identifies a file on disk by device and inode.
+type fileID struct {
+	dev, ino uint64
+}
+
+// RecordUsage marks docs as used now.
+func (s *Store) RecordUsage(slugs ...string) error {
+	if len(slugs) == 0 {
+		return nil
+	}
+	lock, err := s.lockUsage()
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	usage := s.loadUsage()
+	now := time.Now()
+	for _, slug := range slugs {
+		usage[slug] = now
+	}
+	return writeJSON(filepath.Join(s.dataDir, usageFile), usage)
+}
+
+// loadUsage reads the recorded last-use times.
+func (s *Store) loadUsage() map[string]time.Time {
+	usage := make(map[string]time.Time)
+	if data, err := os.ReadFile(filepath.Join(s.dataDir, usageFile)); err == nil {
+		_ = json.Unmarshal(data, &usage)
+	}
+	return usage
+}
+
+// DocSize returns the bytes an installed doc takes on disk. Hard-linked files
+// count once.
+func (s *Store) DocSize(slug string) (int64, error) {
+	size, _, err := s.docSize(slug)
+	return size, err
+}
+
+// docSize measures an installed doc, also returning the files it shares with
+// other hard links (e.g. pages carried over by a delta update).
+func (s *Store) docSize(slug string) (int64, map[fileID]int64, error) {
+	var size int64
+	links := make(map[fileID]int64)
+	err := filepath.WalkDir(s.DocDir(slug), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if id, ok := linkedFileID(info); ok {
+			if _, seen := links[id]; seen {
+				return nil
+			}
+			links[id] = info.Size()
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to measure %s: %w", slug, err)
+	}
+	return size, links, nil
+}
+
+// Usage returns the disk usage of every installed doc, least recently used first.
+func (s *Store) Usage() ([]DocUsage, error) {
+	lastUsed := s.loadUsage()
+
+	var docs []DocUsage
+	for _, slug := range s.ListInstalled() {
+		size, links, err := s.docSize(slug)
+		if err != nil {
+			return nil, err
+		}
+		doc := DocUsage{Slug: slug, Size: size, LastUsed: lastUsed[slug], links: links}
+		if meta, err := s.LoadMeta(slug); err == nil {
+			doc.Pinned = meta.Pinned
+			if doc.LastUsed.IsZero() {
+				doc.LastUsed = meta.Installed
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].LastUsed.Before(docs[j].LastUsed)
+	})
+	return docs, nil
+}
+
+// PruneCandidates picks the least recently used docs to remove so that the
+// total size drops to maxSize. Pinned docs and the docs in keep are never
+// picked. It also returns the current total size. Files hard-linked between
+// docs count once, and the Size of each candidate is the space removing it
+// frees: shared files are only freed with the last doc linking them.
+func PruneCandidates(docs []DocUsage, maxSize int64, keep map[string]bool) ([]DocUsage, int64) {
+	var total int64
+	refs := make(map[fileID]int)
+	for _, doc := range docs {
+		total += doc.Size
+		for id, size := range doc.links {
+			if refs[id] > 0 {
+				total -= size
+			}
+			refs[id]++
+		}
+	}
+
+	var candidates []DocUsage
+	remaining := total
+	for _, doc := range docs {
+		if remaining <= maxSize {
+			break
+		}
+		if doc.Pinned || keep[doc.Slug] {
+			continue
+		}
+		for id, size := range doc.links {
+			if refs[id]--; refs[id] > 0 {
+				doc.Size -= size
+			}
+		}
+		candidates = append(candidates, doc)
+		remaining -= doc.Size
+	}
+	return candidates, total
+}
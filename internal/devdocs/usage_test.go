@@ -0,0 +1,148 @@
+package devdocs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	manifest := []Doc{{Slug: "go"}, {Slug: "react"}, {Slug: "rust"}}
+	for _, doc := range manifest {
+		db := map[string]string{"index": "<h1>" + doc.Slug + "</h1>"}
+		if _, err := store.Install(doc.Slug, &Index{}, db, manifest); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// rust is never used, so its install time counts
+	if err := store.RecordUsage("react"); err != nil {
+		t.Fatalf("RecordUsage() error = %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := store.RecordUsage("go"); err != nil {
+		t.Fatalf("RecordUsage() error = %v", err)
+	}
+
+	docs, err := store.Usage()
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	var slugs []string
+	for _, doc := range docs {
+		slugs = append(slugs, doc.Slug)
+		if doc.Size <= 0 {
+			t.Errorf("Usage() size of %s = %d, want > 0", doc.Slug, doc.Size)
+		}
+	}
+	if want := []string{"rust", "react", "go"}; !reflect.DeepEqual(slugs, want) {
+		t.Errorf("Usage() order = %v, want %v", slugs, want)
+	}
+
+	if err := store.Uninstall("react"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.loadUsage()["react"]; ok {
+		t.Error("Uninstall() kept the usage record")
+	}
+}
+
+func TestPruneCandidates(t *testing.T) {
+	t.Parallel()
+
+	// Least recently used first
+	docs := []DocUsage{
+		{Slug: "cpp", Size: 400},
+		{Slug: "node~18", Size: 300, Pinned: true},
+		{Slug: "rust", Size: 200},
+		{Slug: "go", Size: 100},
+	}
+
+	tests := []struct {
+		name    string
+		maxSize int64
+		keep    map[string]bool
+		want    []string
+	}{
+		{name: "Under quota", maxSize: 1000},
+		{name: "Evicts least recently used", maxSize: 600, want: []string{"cpp"}},
+		{name: "Skips pinned docs", maxSize: 400, want: []string{"cpp", "rust"}},
+		{name: "Skips kept docs", maxSize: 600, keep: map[string]bool{"cpp": true}, want: []string{"rust", "go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			candidates, total := PruneCandidates(docs, tt.maxSize, tt.keep)
+			if total != 1000 {
+				t.Errorf("PruneCandidates() total = %d, want 1000", total)
+			}
+			var got []string
+			for _, doc := range candidates {
+				got = append(got, doc.Slug)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PruneCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneCandidates_HardLinks(t *testing.T) {
+	t.Parallel()
+
+	// go~1.22 and go share a 50-byte page; rust has a page linked elsewhere
+	shared, other := fileID{dev: 1, ino: 1}, fileID{dev: 1, ino: 2}
+	docs := []DocUsage{
+		{Slug: "go~1.22", Size: 100, links: map[fileID]int64{shared: 50}},
+		{Slug: "go", Size: 150, links: map[fileID]int64{shared: 50}},
+		{Slug: "rust", Size: 100, links: map[fileID]int64{other: 30}},
+	}
+
+	candidates, total := PruneCandidates(docs, 100, nil)
+	if total != 300 {
+		t.Errorf("PruneCandidates() total = %d, want 300", total)
+	}
+	got := make(map[string]int64)
+	for _, doc := range candidates {
+		got[doc.Slug] = doc.Size
+	}
+	// The shared page is only freed with go, the last doc linking it
+	want := map[string]int64{"go~1.22": 50, "go": 150}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PruneCandidates() freed = %v, want %v", got, want)
+	}
+}
+
+func TestDocSize_HardLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hard links are measured per link on Windows")
+	}
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	page := "<p>" + string(make([]byte, 1000)) + "</p>"
+	if _, err := store.Install("go", &Index{}, map[string]string{"fmt": page}, []Doc{{Slug: "go"}}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := store.DocSize("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second link to the same page takes no more space
+	content := filepath.Join(store.DocDir("go"), "content")
+	if err := os.Link(filepath.Join(content, "fmt.html"), filepath.Join(content, "fmt-copy.html")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+	if after, err := store.DocSize("go"); err != nil || after != before {
+		t.Errorf("DocSize() with a hard link = %d, %v, want %d", after, err, before)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package devdocs
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// linkedFileID returns the device and inode of a file with more than one hard
+// link, so it is only measured once.
+func linkedFileID(info fs.FileInfo) (fileID, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink < 2 {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package devdocs
+
+import "io/fs"
+
+// linkedFileID reports no link: Windows file info has no link count, so hard
+// links are measured once per link.
+func linkedFileID(info fs.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
@@ -0,0 +1,185 @@
+package devdocs
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// checksumsFile records the SHA-256 of every page as installed (path -> hex digest).
+const checksumsFile = "checksums.json"
+
+// VerifyResult describes the integrity of an installed doc.
+type VerifyResult struct {
+	Slug       string
+	Pages      int      // Pages checked
+	Missing    []string // Pages without a content file
+	Corrupted  []string // Pages that can't be read or don't match their checksum
+	Unverified bool     // No checksums recorded (installed by an older version), only presence was checked
+}
+
+// OK reports whether no broken pages were found.
+func (r *VerifyResult) OK() bool {
+	return len(r.Missing) == 0 && len(r.Corrupted) == 0
+}
+
+// Broken returns the missing and corrupted pages.
+func (r *VerifyResult) Broken() []string {
+	return append(append([]string(nil), r.Missing...), r.Corrupted...)
+}
+
+// pageChecksum returns the hex SHA-256 of a page's (plain) content.
+func pageChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadChecksums reads the recorded page checksums of a doc.
+func (s *Store) loadChecksums(slug string) (map[string]string, error) {
+	data, err := s.readDocFile(slug, filepath.Join(s.DocDir(slug), checksumsFile))
+	if err != nil {
+		return nil, err
+	}
+	var checksums map[string]string
+	if err := json.Unmarshal(data, &checksums); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checksums: %w", err)
+	}
+	return checksums, nil
+}
+
+// writeChecksums saves the page checksums of a doc in docDir. Encrypted docs
+// keep them encrypted: digests of the plaintext would let anyone confirm
+// guessed page content.
+func writeChecksums(docDir string, checksums map[string]string, aead cipher.AEAD) error {
+	data, err := json.MarshalIndent(checksums, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeDocFile(filepath.Join(docDir, checksumsFile), data, aead)
+}
+
+// Verify checks that every page of an installed doc is present and matches
+// the checksum recorded at install time. Docs installed without checksums are
+// checked against the pages referenced by their index.
+func (s *Store) Verify(slug string) (*VerifyResult, error) {
+	index, err := s.LoadIndex(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{Slug: slug}
+	checksums, err := s.loadChecksums(slug)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		result.Unverified = true
+		checksums = make(map[string]string)
+		for _, entry := range index.Entries {
+			page, _ := SplitPath(entry.Path)
+			checksums[page] = ""
+		}
+	default:
+		return nil, err
+	}
+
+	pages := make([]string, 0, len(checksums))
+	for page := range checksums {
+		pages = append(pages, page)
+	}
+	sort.Strings(pages)
+
+	for _, page := range pages {
+		result.Pages++
+		data, err := s.readDocFile(slug, s.ContentPath(slug, page))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			result.Missing = append(result.Missing, page)
+		case errors.Is(err, ErrPassphraseRequired), errors.Is(err, ErrWrongPassphrase):
+			return nil, err
+		case err != nil:
+			result.Corrupted = append(result.Corrupted, page)
+		case checksums[page] != "" && pageChecksum(string(data)) != checksums[page]:
+			result.Corrupted = append(result.Corrupted, page)
+		}
+	}
+
+	return result, nil
+}
+
+// Repair rewrites the given pages of an installed doc from source, skipping
+// all others, and returns how many were repaired. Pages are compressed and
+// encrypted like the rest of the doc.
+func (s *Store) Repair(slug string, pages []string, source ContentSource) (int, error) {
+	lock, err := s.lockDoc(slug)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.release()
+
+	meta, err := s.LoadMeta(slug)
+	if err != nil {
+		return 0, err
+	}
+	var aead cipher.AEAD
+	if meta.Encryption != nil {
+		if aead, err = s.cipherFor(slug); err != nil {
+			return 0, err
+		}
+	}
+
+	want := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		want[page] = true
+	}
+	checksums, err := s.loadChecksums(slug)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	contentDir := filepath.Join(s.DocDir(slug), "content")
+	repaired := 0
+	err = source(func(path, content string) error {
+		if !want[path] {
+			return nil
+		}
+		if len(meta.Process) > 0 {
+			if meta.Originals {
+				if err := writePage(filepath.Join(s.DocDir(slug), originalsDir), path, content, meta.Compressed, aead); err != nil {
+					return err
+				}
+			}
+			var err error
+			if content, err = processPage(content, meta.Process); err != nil {
+				return fmt.Errorf("failed to process %s: %w", path, err)
+			}
+		}
+		if err := writePage(contentDir, path, content, meta.Compressed, aead); err != nil {
+			return err
+		}
+		if checksums != nil {
+			checksums[path] = pageChecksum(content)
+		}
+		delete(want, path)
+		repaired++
+		return nil
+	})
+	if err != nil {
+		return repaired, err
+	}
+
+	if checksums != nil {
+		if err := writeChecksums(s.DocDir(slug), checksums, aead); err != nil {
+			return repaired, fmt.Errorf("failed to save checksums: %w", err)
+		}
+	}
+	if len(want) > 0 {
+		return repaired, fmt.Errorf("%d page(s) not found in the downloaded content", len(want))
+	}
+	return repaired, nil
+}
@@ -0,0 +1,118 @@
+package devdocs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVerifyAndRepair(t *testing.T) {
+	t.Parallel()
+
+	index := &Index{Entries: []Entry{
+		{Name: "fmt", Path: "fmt/index", Type: "fmt"},
+		{Name: "fmt.Println", Path: "fmt/index#Println", Type: "fmt"},
+		{Name: "http", Path: "net/http/index", Type: "net"},
+		{Name: "os", Path: "os/index", Type: "os"},
+	}}
+	db := map[string]string{
+		"fmt/index":      "<h1>fmt</h1>",
+		"net/http/index": "<h1>net/http</h1>",
+		"os/index":       "<h1>os</h1>",
+	}
+	manifest := []Doc{{Slug: "go", Mtime: 1}}
+
+	tests := []struct {
+		name           string
+		opts           []StoreOption
+		dropChecksums  bool
+		wantCorrupted  []string
+		wantUnverified bool
+	}{
+		{name: "Plain doc", wantCorrupted: []string{"os/index"}},
+		{name: "Compressed doc", opts: []StoreOption{WithCompression(true)}, wantCorrupted: []string{"os/index"}},
+		{name: "Encrypted doc", opts: []StoreOption{WithEncryption("hunter2")}, wantCorrupted: []string{"os/index"}},
+		// Without checksums, a changed page can't be told apart from a good one
+		{name: "Doc without checksums", dropChecksums: true, wantUnverified: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewStore(append([]StoreOption{WithDataDir(t.TempDir())}, tt.opts...)...)
+			if _, err := store.Install("go", index, db, manifest); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			if tt.dropChecksums {
+				if err := os.Remove(filepath.Join(store.DocDir("go"), checksumsFile)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := store.Verify("go")
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !result.OK() || result.Pages != 3 {
+				t.Fatalf("Verify() of a fresh install = %+v, want 3 good pages", result)
+			}
+
+			// Break the install: one page missing, one overwritten
+			if err := os.Remove(store.ContentPath("go", "net/http/index")); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(store.ContentPath("go", "os/index"), []byte("<h1>garbage</h1>"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err = store.Verify("go")
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Missing, []string{"net/http/index"}) {
+				t.Errorf("Verify() Missing = %v, want [net/http/index]", result.Missing)
+			}
+			if !reflect.DeepEqual(result.Corrupted, tt.wantCorrupted) {
+				t.Errorf("Verify() Corrupted = %v, want %v", result.Corrupted, tt.wantCorrupted)
+			}
+			if result.Unverified != tt.wantUnverified {
+				t.Errorf("Verify() Unverified = %v, want %v", result.Unverified, tt.wantUnverified)
+			}
+
+			repaired, err := store.Repair("go", result.Broken(), MapSource(db))
+			if err != nil {
+				t.Fatalf("Repair() error = %v", err)
+			}
+			if repaired != len(result.Broken()) {
+				t.Errorf("Repair() = %d, want %d", repaired, len(result.Broken()))
+			}
+
+			result, err = store.Verify("go")
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !result.OK() {
+				t.Errorf("Verify() after Repair() = %+v, want no broken pages", result)
+			}
+			if got, _ := store.LoadContent("go", "net/http/index"); got != db["net/http/index"] {
+				t.Errorf("LoadContent() after Repair() = %q, want %q", got, db["net/http/index"])
+			}
+		})
+	}
+}
+
+func TestRepair_PageNotInSource(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(WithDataDir(t.TempDir()))
+	db := map[string]string{"fmt/index": "<h1>fmt</h1>"}
+	if _, err := store.Install("go", &Index{}, db, []Doc{{Slug: "go"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Repair("go", []string{"gone/index"}, MapSource(db)); err == nil {
+		t.Error("Repair() of a page missing upstream should fail")
+	}
+}
@@ -0,0 +1,74 @@
+// Package devdocs provides types and client for interacting with the DevDocs API
+package devdocs
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const devdocsWebURL = "https://devdocs.io"
+
+//go:embed weburls.yaml
+var webURLsYAML []byte
+
+// webURLTemplate maps DevDocs entry paths to an upstream documentation site
+type webURLTemplate struct {
+	Template    string `yaml:"template"`     // URL with {path} and {version} placeholders
+	StripSuffix string `yaml:"strip_suffix"` // Suffix removed from the path (e.g., "/index")
+}
+
+var (
+	webURLsOnce sync.Once
+	webURLs     map[string]webURLTemplate
+	webURLsErr  error
+)
+
+// loadWebURLs parses the embedded URL template mapping once
+func loadWebURLs() (map[string]webURLTemplate, error) {
+	webURLsOnce.Do(func() {
+		if err := yaml.Unmarshal(webURLsYAML, &webURLs); err != nil {
+			webURLsErr = fmt.Errorf("failed to parse web URL templates: %w", err)
+		}
+	})
+	return webURLs, webURLsErr
+}
+
+// SplitPath splits an entry path like "net/http/index#Client" into the
+// content page ("net/http/index") and anchor ("Client")
+func SplitPath(path string) (page, anchor string) {
+	page, anchor, _ = strings.Cut(path, "#")
+	return page, anchor
+}
+
+// SplitSlug splits a slug like "react~18" into the doc name and version
+func SplitSlug(slug string) (name, version string) {
+	name, version, _ = strings.Cut(slug, "~")
+	return name, version
+}
+
+// WebURL returns the upstream web URL for an entry, falling back to
+// https://devdocs.io/<slug>/<path> for docs without a known template
+func WebURL(slug, path string) string {
+	name, version := SplitSlug(slug)
+
+	templates, err := loadWebURLs()
+	tmpl, ok := templates[name]
+	if err != nil || !ok {
+		return fmt.Sprintf("%s/%s/%s", devdocsWebURL, slug, path)
+	}
+
+	page, anchor := SplitPath(path)
+	if tmpl.StripSuffix != "" {
+		page = strings.TrimSuffix(page, tmpl.StripSuffix)
+	}
+
+	url := strings.NewReplacer("{path}", page, "{version}", version).Replace(tmpl.Template)
+	if anchor != "" {
+		url += "#" + anchor
+	}
+	return url
+}
@@ -0,0 +1,76 @@
+// Package devdocs tests for upstream web URL reconstruction
+package devdocs
+
+import "testing"
+
+func TestWebURLTemplatesParse(t *testing.T) {
+	templates, err := loadWebURLs()
+	if err != nil {
+		t.Fatalf("loadWebURLs() error = %v", err)
+	}
+	for name, tmpl := range templates {
+		if tmpl.Template == "" {
+			t.Errorf("Template for %q is empty", name)
+		}
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	tests := []struct {
+		name string
+		slug string
+		path string
+		want string
+	}{
+		{
+			name: "Go package with anchor",
+			slug: "go",
+			path: "net/http/index#Client",
+			want: "https://pkg.go.dev/net/http#Client",
+		},
+		{
+			name: "Versioned Python page",
+			slug: "python~3.12",
+			path: "library/functions#len",
+			want: "https://docs.python.org/3.12/library/functions.html#len",
+		},
+		{
+			name: "Python index page",
+			slug: "python~3.12",
+			path: "library/index",
+			want: "https://docs.python.org/3.12/library/index.html",
+		},
+		{
+			name: "Python anchored index page",
+			slug: "python~3.12",
+			path: "reference/index#the-python-language-reference",
+			want: "https://docs.python.org/3.12/reference/index.html#the-python-language-reference",
+		},
+		{
+			name: "Fallback to devdocs.io",
+			slug: "react~18",
+			path: "reference/react/usestate",
+			want: "https://devdocs.io/react~18/reference/react/usestate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WebURL(tt.slug, tt.path); got != tt.want {
+				t.Errorf("WebURL(%q, %q) = %q, want %q", tt.slug, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	page, anchor := SplitPath("net/http/index#Client.Do")
+	if page != "net/http/index" || anchor != "Client.Do" {
+		t.Errorf("SplitPath() = %q, %q, want net/http/index, Client.Do", page, anchor)
+	}
+
+	page, anchor = SplitPath("reference/react/usestate")
+	if page != "reference/react/usestate" || anchor != "" {
+		t.Errorf("SplitPath() = %q, %q, want reference/react/usestate, \"\"", page, anchor)
+	}
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// preformatted are the elements whose whitespace is significant.
+var preformatted = []string{"pre", "textarea", "script", "style"}
+
+// StripStyles removes style elements and inline style attributes from an
+// HTML fragment, which only matter to browsers.
+func StripStyles(htmlContent []byte) ([]byte, error) {
+	return transformFragment(htmlContent, func(body *html.Node) {
+		removeElements(body, "style")
+		removeAttr(body, "style")
+	})
+}
+
+// Minify shrinks an HTML fragment by dropping comments and collapsing runs of
+// whitespace outside preformatted elements to a single space.
+func Minify(htmlContent []byte) ([]byte, error) {
+	return transformFragment(htmlContent, minifyNode)
+}
+
+func minifyNode(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type == html.TextNode:
+			c.Data = collapseSpace(c.Data)
+		case c.Type == html.ElementNode && !slices.Contains(preformatted, c.Data):
+			minifyNode(c)
+		}
+		c = next
+	}
+}
+
+// collapseSpace replaces every run of whitespace in s with a single space.
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// removeAttr deletes the attribute key from n and all its descendants.
+func removeAttr(n *html.Node, key string) {
+	if n.Type == html.ElementNode {
+		n.Attr = slices.DeleteFunc(n.Attr, func(a html.Attribute) bool { return a.Key == key })
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		removeAttr(c, key)
+	}
+}
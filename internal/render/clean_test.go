@@ -0,0 +1,57 @@
+package render
+
+import "testing"
+
+func TestStripStyles(t *testing.T) {
+	input := `<style>p { color: red }</style><p style="color: blue" class="note">Text <code style="font-weight: bold">x</code></p>`
+	want := `<p class="note">Text <code>x</code></p>`
+
+	got, err := StripStyles([]byte(input))
+	if err != nil {
+		t.Fatalf("StripStyles() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("StripStyles() = %q, want %q", got, want)
+	}
+}
+
+func TestMinify(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Collapses whitespace",
+			input: "<h1>Title</h1>\n\n  <p>Some   text\n  here</p>\n",
+			want:  "<h1>Title</h1> <p>Some text here</p> ",
+		},
+		{
+			name:  "Keeps inline spacing",
+			input: "<p><b>a</b> <i>b</i></p>",
+			want:  "<p><b>a</b> <i>b</i></p>",
+		},
+		{
+			name:  "Keeps preformatted text",
+			input: "<pre>func main() {\n    fmt.Println()\n}</pre>",
+			want:  "<pre>func main() {\n    fmt.Println()\n}</pre>",
+		},
+		{
+			name:  "Drops comments",
+			input: "<p>a<!-- generated --></p>",
+			want:  "<p>a</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Minify([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Minify() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Minify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
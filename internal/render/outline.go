@@ -0,0 +1,196 @@
+// Package render handles converting HTML documentation to text and markdown.
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ErrAnchorNotFound is returned when a section anchor does not exist in a page.
+var ErrAnchorNotFound = errors.New("anchor not found")
+
+// Heading is a single heading in a page outline.
+type Heading struct {
+	Level  int    // Heading level (1-6)
+	Text   string // Heading text with whitespace collapsed
+	Anchor string // Element id usable as #anchor (may be empty)
+}
+
+// Outline returns the headings of an HTML page in document order.
+func Outline(htmlContent []byte) ([]Heading, error) {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	var headings []Heading
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if level := headingLevel(n); level > 0 {
+			headings = append(headings, Heading{
+				Level:  level,
+				Text:   strings.Join(strings.Fields(textContent(n)), " "),
+				Anchor: headingAnchor(n),
+			})
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return headings, nil
+}
+
+// Section extracts the part of an HTML page identified by anchor.
+// For a heading, the section runs until the next heading of the same or higher level.
+// Returns ErrAnchorNotFound if no element has the given id.
+func Section(htmlContent []byte, anchor string) ([]byte, error) {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+
+	target := findAnchor(doc, anchor)
+	if target == nil {
+		return nil, fmt.Errorf("%w: #%s", ErrAnchorNotFound, anchor)
+	}
+
+	// Anchors on (or inside) a heading select the heading's section
+	start := target
+	for n := target; n != nil; n = n.Parent {
+		if headingLevel(n) > 0 {
+			start = n
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	level := headingLevel(start)
+	if level == 0 {
+		// A definition term (e.g. a Sphinx signature) owns the descriptions after it
+		if term := ancestor(target, "dt"); term != nil {
+			for n := term; n != nil; n = n.NextSibling {
+				if n != term && n.Type == html.ElementNode && n.Data == "dt" {
+					break
+				}
+				if err := html.Render(&buf, n); err != nil {
+					return nil, fmt.Errorf("rendering section: %w", err)
+				}
+			}
+			return buf.Bytes(), nil
+		}
+	}
+	if level == 0 && strings.TrimSpace(textContent(start)) != "" {
+		// Plain element with content: the element, or the block holding an
+		// inline one (e.g. <code id="x"> in a paragraph), is the section
+		for start.Parent != nil && start.Parent.Type == html.ElementNode && start.Parent.Data != "body" && isInline(start) {
+			start = start.Parent
+		}
+		if err := html.Render(&buf, start); err != nil {
+			return nil, fmt.Errorf("rendering section: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	if level == 0 {
+		// Empty marker element (e.g. <a name="x"></a>): run until the next heading
+		level = 7
+	}
+
+	for n := start; n != nil; n = n.NextSibling {
+		if n != start {
+			if l := headingLevel(n); l > 0 && l <= level {
+				break
+			}
+		}
+		if err := html.Render(&buf, n); err != nil {
+			return nil, fmt.Errorf("rendering section: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// inlineElements are elements that are part of a surrounding block of text.
+var inlineElements = []string{"a", "abbr", "b", "code", "em", "i", "kbd", "samp", "small", "span", "strong", "sub", "sup", "tt", "var"}
+
+// isInline reports whether n is an inline element.
+func isInline(n *html.Node) bool {
+	return n.Type == html.ElementNode && slices.Contains(inlineElements, n.Data)
+}
+
+// ancestor returns n or its closest ancestor with the given tag, or nil.
+func ancestor(n *html.Node, tag string) *html.Node {
+	for ; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode && n.Data == tag {
+			return n
+		}
+	}
+	return nil
+}
+
+// headingLevel returns 1-6 for h1-h6 elements and 0 otherwise.
+func headingLevel(n *html.Node) int {
+	if n.Type != html.ElementNode || len(n.Data) != 2 || n.Data[0] != 'h' {
+		return 0
+	}
+	if l := int(n.Data[1] - '0'); l >= 1 && l <= 6 {
+		return l
+	}
+	return 0
+}
+
+// headingAnchor returns the id of a heading, or of an anchor element inside it.
+func headingAnchor(n *html.Node) string {
+	if id := anchorID(n); id != "" {
+		return id
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			if id := headingAnchor(c); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// anchorID returns the id (or legacy name attribute on <a>) of an element.
+func anchorID(n *html.Node) string {
+	for _, attr := range n.Attr {
+		if attr.Key == "id" || (attr.Key == "name" && n.Data == "a") {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// findAnchor returns the first element whose anchor id matches.
+func findAnchor(n *html.Node, anchor string) *html.Node {
+	if n.Type == html.ElementNode && anchorID(n) == anchor {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findAnchor(c, anchor); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent returns the concatenated text of a node and its descendants.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
@@ -0,0 +1,133 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const outlineFixture = `<h1>net/http</h1>
+<p>Package http provides HTTP client and server implementations.</p>
+<h2 id="pkg-overview">Overview</h2>
+<p>Overview text.</p>
+<h2 id="Client">type <a href="#Client">Client</a></h2>
+<p>A Client is an HTTP client.</p>
+<h3 id="Client.Do">func (*Client) Do</h3>
+<p>Do sends an HTTP request.</p>
+<h2 id="Server">type Server</h2>
+<p>A Server defines parameters for running an HTTP server.</p>
+<p id="note">Standalone note.</p>`
+
+func TestOutline(t *testing.T) {
+	headings, err := Outline([]byte(outlineFixture))
+	if err != nil {
+		t.Fatalf("Outline() error = %v", err)
+	}
+
+	want := []Heading{
+		{Level: 1, Text: "net/http"},
+		{Level: 2, Text: "Overview", Anchor: "pkg-overview"},
+		{Level: 2, Text: "type Client", Anchor: "Client"},
+		{Level: 3, Text: "func (*Client) Do", Anchor: "Client.Do"},
+		{Level: 2, Text: "type Server", Anchor: "Server"},
+	}
+	if len(headings) != len(want) {
+		t.Fatalf("Outline() returned %d headings, want %d: %+v", len(headings), len(want), headings)
+	}
+	for i := range want {
+		if headings[i] != want[i] {
+			t.Errorf("headings[%d] = %+v, want %+v", i, headings[i], want[i])
+		}
+	}
+}
+
+// sphinxFixture is a Python-docs-style page, where entries are definition lists.
+const sphinxFixture = `<h1>os.path</h1>
+<dl class="py function">
+<dt class="sig" id="os.path.join"><span class="sig-name">os.path.join</span>(<em>path</em>, <em>*paths</em>)</dt>
+<dd><p>Join one or more path segments intelligently.</p></dd>
+</dl>
+<dl class="py function">
+<dt class="sig" id="os.path.split"><span class="sig-name">os.path.split</span>(<em>path</em>)</dt>
+<dd><p>Split the pathname <em>path</em> into a pair.</p></dd>
+</dl>
+<p>See also <code id="pathlib">pathlib</code> for an object-oriented API.</p>`
+
+func TestSection(t *testing.T) {
+	tests := []struct {
+		name        string
+		html        string
+		anchor      string
+		contains    []string
+		notContains []string
+		wantErr     error
+	}{
+		{
+			name:        "heading section includes subsections",
+			anchor:      "Client",
+			contains:    []string{"A Client is an HTTP client", "Do sends an HTTP request"},
+			notContains: []string{"Overview text", "A Server defines"},
+		},
+		{
+			name:        "subsection stops at next heading",
+			anchor:      "Client.Do",
+			contains:    []string{"Do sends an HTTP request"},
+			notContains: []string{"A Client is an HTTP client", "A Server defines"},
+		},
+		{
+			name:        "plain element",
+			anchor:      "note",
+			contains:    []string{"Standalone note"},
+			notContains: []string{"A Server defines"},
+		},
+		{
+			name:        "definition term includes its description",
+			html:        sphinxFixture,
+			anchor:      "os.path.join",
+			contains:    []string{"os.path.join", "Join one or more path segments"},
+			notContains: []string{"Split the pathname"},
+		},
+		{
+			name:        "inline element selects its block",
+			html:        sphinxFixture,
+			anchor:      "pathlib",
+			contains:    []string{"See also", "object-oriented API"},
+			notContains: []string{"Join one or more"},
+		},
+		{
+			name:    "missing anchor",
+			anchor:  "nope",
+			wantErr: ErrAnchorNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := tt.html
+			if page == "" {
+				page = outlineFixture
+			}
+			section, err := Section([]byte(page), tt.anchor)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Section() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Section() error = %v", err)
+			}
+
+			for _, expected := range tt.contains {
+				if !strings.Contains(string(section), expected) {
+					t.Errorf("Expected section to contain %q, got:\n%s", expected, section)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(string(section), unwanted) {
+					t.Errorf("Section should NOT contain %q, got:\n%s", unwanted, section)
+				}
+			}
+		})
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
 
 	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 
 	readability "codeberg.org/readeck/go-readability"
 )
@@ -90,18 +93,101 @@ func (r *Renderer) renderMarkdown(htmlContent []byte) (string, error) {
 	return md, nil
 }
 
-// extractMainContent uses readability to extract the main readable content.
-// This removes navigation, sidebar, footer, ads, and other non-content elements.
+// documentPattern matches markup that only appears in full HTML documents.
+var documentPattern = regexp.MustCompile(`(?i)<(!doctype|html|head|body)[\s>]`)
+
+// isDocument reports whether the input is a full HTML document (as stored in
+// Dash docsets) rather than a body fragment (as stored in DevDocs db.json).
+func isDocument(htmlContent []byte) bool {
+	return documentPattern.Match(htmlContent)
+}
+
+// extractMainContent returns the readable content of a page.
+// Full documents go through readability to remove navigation, sidebars, footers
+// and other non-content elements. DevDocs fragments are already cleaned and
+// readability would drop parts of them, so they only have scripts and styles removed.
 func (r *Renderer) extractMainContent(htmlContent []byte) ([]byte, error) {
+	if !isDocument(htmlContent) {
+		return cleanFragment(htmlContent)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML: %w", err)
+	}
+	title := firstHeading(doc)
+
 	// Parse the URL for readability (we don't have a real URL for docset files)
 	baseURL, _ := url.Parse("http://localhost/docset")
 
-	article, err := readability.FromReader(bytes.NewReader(htmlContent), baseURL)
+	article, err := readability.FromDocument(doc, baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("readability extraction: %w", err)
 	}
 
-	return []byte(article.Content), nil
+	// Readability moves the page title out of the content; put it back
+	content := article.Content
+	if title != "" && !strings.Contains(article.TextContent, title) {
+		content = "<h1>" + html.EscapeString(title) + "</h1>" + content
+	}
+
+	return []byte(content), nil
+}
+
+// cleanFragment parses a body fragment into a consistent tree and removes
+// elements that never hold readable content.
+func cleanFragment(htmlContent []byte) ([]byte, error) {
+	return transformFragment(htmlContent, func(body *html.Node) {
+		removeElements(body, "script", "style", "noscript")
+	})
+}
+
+// transformFragment parses a body fragment, lets fn modify it and renders it back.
+func transformFragment(htmlContent []byte, fn func(body *html.Node)) ([]byte, error) {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(bytes.NewReader(htmlContent), body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing HTML fragment: %w", err)
+	}
+
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	fn(body)
+
+	var buf bytes.Buffer
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return nil, fmt.Errorf("rendering fragment: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// removeElements deletes all descendants of n with the given tag names.
+func removeElements(n *html.Node, tags ...string) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode && slices.Contains(tags, c.Data) {
+			n.RemoveChild(c)
+		} else {
+			removeElements(c, tags...)
+		}
+		c = next
+	}
+}
+
+// firstHeading returns the text of the first h1 in a document.
+func firstHeading(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "h1" {
+		return strings.Join(strings.Fields(textContent(n)), " ")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if title := firstHeading(c); title != "" {
+			return title
+		}
+	}
+	return ""
 }
 
 // extractText recursively extracts text from HTML nodes.
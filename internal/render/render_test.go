@@ -2,6 +2,8 @@
 package render
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -151,3 +153,91 @@ func TestRenderTextMode(t *testing.T) {
 		t.Errorf("Text mode should have content, got: %s", result)
 	}
 }
+
+func TestRenderFixtures(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixture     string
+		format      Format
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:    "DevDocs fragment keeps all content",
+			fixture: "devdocs_fragment.html",
+			format:  FormatMD,
+			contains: []string{
+				"# Array.prototype.map()",
+				"[Array](../array)",
+				"### Parameters",
+				"A value to use as `this`",
+				"## Browser compatibility",
+			},
+			notContains: []string{"localhost"},
+		},
+		{
+			name:     "DevDocs fragment as text",
+			fixture:  "devdocs_fragment.html",
+			format:   FormatText,
+			contains: []string{"Array.prototype.map()", "Return value", "Browser compatibility"},
+		},
+		{
+			name:        "Dash document keeps title and drops chrome",
+			fixture:     "dash_document.html",
+			format:      FormatMD,
+			contains:    []string{"# NSString", "## Overview", "- (NSUInteger)length;"},
+			notContains: []string{"NSDictionary", "Copyright", "font-family", "analytics"},
+		},
+		{
+			name:        "Dash document as text",
+			fixture:     "dash_document.html",
+			format:      FormatText,
+			contains:    []string{"NSString", "Instance Methods"},
+			notContains: []string{"NSDictionary", "Copyright"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := New(tt.format).Render(input)
+			if err != nil {
+				t.Fatalf("Renderer.Render() error = %v", err)
+			}
+
+			for _, expected := range tt.contains {
+				if !strings.Contains(result, expected) {
+					t.Errorf("Expected output to contain %q, got:\n%s", expected, result)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("Output should NOT contain %q, but it does. Got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestIsDocument(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"<!DOCTYPE html><html><body><p>x</p></body></html>", true},
+		{"<html lang=\"en\"><p>x</p></html>", true},
+		{"<head><title>x</title></head><p>x</p>", true},
+		{"<h1>Title</h1><p>Fragment with <a href=\"#body\">body</a> link</p>", false},
+		{"<p>Mentions &lt;html&gt; in text</p>", false},
+	}
+
+	for _, tt := range tests {
+		if got := isDocument([]byte(tt.input)); got != tt.want {
+			t.Errorf("isDocument(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
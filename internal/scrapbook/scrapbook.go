@@ -0,0 +1,189 @@
+// Package scrapbook collects rendered documentation snippets into a
+// per-project markdown file.
+package scrapbook
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFile is the scrapbook file name used in the current directory.
+const DefaultFile = "dsearch-scrapbook.md"
+
+// markerPrefix starts the HTML comment that delimits each snippet. It keeps
+// the file readable as plain markdown while letting Load recover snippets.
+const markerPrefix = "<!-- dsearch:snippet "
+
+// sourcePrefix starts the attribution line of each snippet.
+const sourcePrefix = "> Source: "
+
+// Snippet is a rendered documentation entry with its source attribution.
+type Snippet struct {
+	Name    string    // Entry name
+	Slug    string    // Doc the entry belongs to
+	Path    string    // Entry path (may include #anchor)
+	URL     string    // Upstream web URL
+	Added   time.Time // When the snippet was captured
+	Content string    // Rendered markdown
+}
+
+// Append adds a snippet to the end of the scrapbook at path, creating it if needed.
+func Append(path string, s Snippet) error {
+	if s.Added.IsZero() {
+		s.Added = time.Now()
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open scrapbook: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(format(s)); err != nil {
+		return fmt.Errorf("failed to write scrapbook: %w", err)
+	}
+	return nil
+}
+
+// Load reads all snippets from the scrapbook at path. A missing file yields no snippets.
+func Load(path string) ([]Snippet, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scrapbook: %w", err)
+	}
+	defer f.Close()
+
+	var snippets []Snippet
+	var current *Snippet
+	var body []string
+
+	flush := func() {
+		if current != nil {
+			current.Content = extractBody(body)
+			snippets = append(snippets, *current)
+		}
+		body = nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, markerPrefix) {
+			flush()
+			s, err := parseMarker(line)
+			if err != nil {
+				return nil, err
+			}
+			current = &s
+			continue
+		}
+		body = append(body, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read scrapbook: %w", err)
+	}
+	flush()
+
+	return snippets, nil
+}
+
+// Export renders snippets as a single markdown document with a table of contents.
+func Export(title string, snippets []Snippet) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for i, s := range snippets {
+		fmt.Fprintf(&b, "%d. %s (%s)\n", i+1, s.Name, s.Slug)
+	}
+	for _, s := range snippets {
+		b.WriteString("\n")
+		b.WriteString(s.Markdown())
+	}
+	return b.String()
+}
+
+// format renders a snippet as it is stored in the scrapbook file.
+func format(s Snippet) string {
+	return fmt.Sprintf("%sname=%q slug=%q path=%q url=%q added=%q -->\n%s\n", markerPrefix,
+		s.Name, s.Slug, s.Path, s.URL, s.Added.UTC().Format(time.RFC3339), s.Markdown())
+}
+
+// Markdown renders the snippet's heading, attribution and content.
+func (s Snippet) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s (%s)\n\n", s.Name, s.Slug)
+	fmt.Fprintf(&b, "%s%s — `%s`", sourcePrefix, s.Slug, s.Path)
+	if s.URL != "" {
+		fmt.Fprintf(&b, " — <%s>", s.URL)
+	}
+	b.WriteString("\n\n")
+	b.WriteString(strings.TrimSpace(s.Content))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// parseMarker reads the attributes from a snippet marker line. The key="value"
+// pairs are read left to right, so values quoting other attributes (a name
+// containing path="...") can't be mistaken for them.
+func parseMarker(line string) (Snippet, error) {
+	attrs := strings.TrimPrefix(line, markerPrefix)
+
+	values := make(map[string]string)
+	for {
+		attrs = strings.TrimLeft(attrs, " ")
+		if attrs == "" || strings.HasPrefix(attrs, "-->") {
+			break
+		}
+		key, rest, ok := strings.Cut(attrs, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \"") {
+			return Snippet{}, fmt.Errorf("invalid scrapbook marker: bad attribute %q", attrs)
+		}
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return Snippet{}, fmt.Errorf("invalid scrapbook marker %s: %w", key, err)
+		}
+		values[key], _ = strconv.Unquote(quoted)
+		attrs = rest[len(quoted):]
+	}
+	for _, key := range []string{"name", "slug", "path", "url", "added"} {
+		if _, ok := values[key]; !ok {
+			return Snippet{}, fmt.Errorf("invalid scrapbook marker: missing %s", key)
+		}
+	}
+
+	added, err := time.Parse(time.RFC3339, values["added"])
+	if err != nil {
+		return Snippet{}, fmt.Errorf("invalid scrapbook marker added: %w", err)
+	}
+
+	return Snippet{
+		Name:  values["name"],
+		Slug:  values["slug"],
+		Path:  values["path"],
+		URL:   values["url"],
+		Added: added,
+	}, nil
+}
+
+// extractBody returns a snippet's content without the heading and source line
+// written by Markdown.
+func extractBody(lines []string) string {
+	for _, prefix := range []string{"## ", sourcePrefix} {
+		for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+			lines = lines[1:]
+		}
+		if len(lines) > 0 && strings.HasPrefix(lines[0], prefix) {
+			lines = lines[1:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,132 @@
+package scrapbook
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	added := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	snippets := []Snippet{
+		{
+			Name:    "useState",
+			Slug:    "react",
+			Path:    "reference/react/usestate",
+			URL:     "https://devdocs.io/react/reference/react/usestate",
+			Added:   added,
+			Content: "## Usage\n\nCall `useState` at the top level.",
+		},
+		{
+			Name:    `http.Client "quoted"`,
+			Slug:    "go",
+			Path:    "net/http/index#Client",
+			Added:   added,
+			Content: "A Client is an HTTP client.",
+		},
+	}
+
+	for _, s := range snippets {
+		if err := Append(path, s); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, snippets) {
+		t.Errorf("Load() = %+v, want %+v", got, snippets)
+	}
+}
+
+func TestParseMarker(t *testing.T) {
+	t.Parallel()
+
+	added := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		line    string
+		want    Snippet
+		wantErr bool
+	}{
+		{
+			name: "Plain",
+			line: markerPrefix + `name="fmt" slug="go" path="fmt/index" url="" added="2026-01-02T03:04:05Z" -->`,
+			want: Snippet{Name: "fmt", Slug: "go", Path: "fmt/index", Added: added},
+		},
+		{
+			name: "Earlier value quoting a later key",
+			line: markerPrefix + `name="a path=\"evil\" url=\"x\"" slug="go" path="fmt/index" url="https://example.com" added="2026-01-02T03:04:05Z" -->`,
+			want: Snippet{Name: `a path="evil" url="x"`, Slug: "go", Path: "fmt/index", URL: "https://example.com", Added: added},
+		},
+		{
+			name:    "Key only inside a value",
+			line:    markerPrefix + `name="path=\"evil\"" slug="go" url="" added="2026-01-02T03:04:05Z" -->`,
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated value",
+			line:    markerPrefix + `name="fmt slug="go" -->`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseMarker(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMarker() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMarker() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	t.Parallel()
+
+	got, err := Load(filepath.Join(t.TempDir(), "missing.md"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Load() = %v, want no snippets", got)
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	out := Export("Notes", []Snippet{
+		{Name: "useState", Slug: "react", Path: "usestate", URL: "https://example.com/usestate", Content: "State hook."},
+		{Name: "len", Slug: "go", Path: "builtin/index#len", Content: "Length."},
+	})
+
+	for _, want := range []string{
+		"# Notes\n",
+		"1. useState (react)\n2. len (go)\n",
+		"## useState (react)",
+		"> Source: react — `usestate` — <https://example.com/usestate>",
+		"> Source: go — `builtin/index#len`\n",
+		"State hook.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Export() missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, markerPrefix) {
+		t.Errorf("Export() should not contain snippet markers:\n%s", out)
+	}
+}
@@ -3,19 +3,30 @@ package search
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/sahilm/fuzzy"
 
 	"github.com/icampana/dsearch/internal/devdocs"
 )
 
+// IndexLoader loads the index of an installed doc on demand.
+type IndexLoader func(slug string) (*devdocs.Index, error)
+
 // Engine handles searching across multiple DevDocs indices.
 type Engine struct {
 	indices       []*devdocs.Index
 	indicesBySlug map[string]*devdocs.Index // slug -> Index lookup
 	slugsByIndex  map[*devdocs.Index]string // Index -> slug lookup (for reverse mapping)
 	limit         int
+	types         []string // Optional type filter (canonical or original types)
+
+	// Lazy mode: indices are loaded one doc at a time during a search and
+	// released afterwards, trading speed for a small memory footprint.
+	slugs  []string
+	loader IndexLoader
 }
 
 // New creates a new search engine.
@@ -34,77 +45,164 @@ func New(indices []*devdocs.Index, indicesBySlug map[string]*devdocs.Index, limi
 	}
 }
 
+// NewLazy creates a search engine that loads each doc's index only while
+// searching it, keeping at most one index in memory at a time.
+func NewLazy(slugs []string, loader IndexLoader, limit int) *Engine {
+	return &Engine{
+		slugs:  slugs,
+		loader: loader,
+		limit:  limit,
+	}
+}
+
+// SetTypeFilter restricts searches to entries whose canonical or original type
+// matches one of types. An empty list disables the filter.
+func (e *Engine) SetTypeFilter(types []string) {
+	e.types = types
+}
+
 // Result represents a search result with fuzzy match score.
 type Result struct {
 	devdocs.Entry
 	Slug  string  // Which doc this result is from
 	Score float64 // Fuzzy match score (0-1)
+	URL   string  // Upstream web URL for the entry
+	Kind  string  // Canonical type (see NormalizeType); Entry.Type keeps the original
 }
 
 // Search performs a search across all indices with fuzzy matching.
 // If docSlugs is specified, only those docs are searched.
-// Warns via returned warning string if searching across >10 docs without filtering.
+// Warns via returned warning string (one warning per line) if searching
+// across >10 docs without filtering, or when a lazily loaded index can't be
+// read: that doc is skipped, as when indices are loaded up front.
 func (e *Engine) Search(query string, docSlugs []string) ([]Result, string, error) {
 	var results []Result
-	var warning string
+	var warnings []string
 
-	// Filter indices by slug if specified
-	indicesToSearch := e.indices
-	if len(docSlugs) > 0 {
-		indicesToSearch = make([]*devdocs.Index, 0)
-		for _, slug := range docSlugs {
-			if idx, ok := e.indicesBySlug[slug]; ok {
-				indicesToSearch = append(indicesToSearch, idx)
-			}
+	// Filter docs by slug if specified
+	slugsToSearch := e.searchSlugs(docSlugs)
+	if len(slugsToSearch) == 0 {
+		return nil, "", fmt.Errorf("no matching docs found")
+	}
+
+	// Warn if searching across many docs without filtering
+	if len(slugsToSearch) > 10 && len(docSlugs) == 0 {
+		warnings = append(warnings, fmt.Sprintf("Searching across %d docs. Use -d <doc> for faster results.", len(slugsToSearch)))
+	}
+
+	searched, loaded := 0, 0
+	for _, slug := range slugsToSearch {
+		idx, err := e.index(slug)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		loaded++
+
+		matches, n := e.match(query, slug, idx)
+		results = append(results, matches...)
+		searched += n
+
+		// In lazy mode only the best results are kept between docs
+		if e.loader != nil {
+			results = e.top(results)
 		}
 	}
 
-	if len(indicesToSearch) == 0 {
-		return nil, "", fmt.Errorf("no matching docs found")
+	warning := strings.Join(warnings, "\n")
+	if loaded == 0 {
+		return nil, warning, fmt.Errorf("no documentation could be loaded")
+	}
+	if searched == 0 {
+		return nil, warning, fmt.Errorf("no results found for %q", query)
 	}
 
-	// Warn if searching across many docs without filtering
-	if len(indicesToSearch) > 10 && len(docSlugs) == 0 {
-		warning = fmt.Sprintf("Searching across %d docs. Use -d <doc> for faster results.", len(indicesToSearch))
+	results = e.top(results)
+
+	// Resolve web URLs and canonical types only for the results we return
+	for i := range results {
+		results[i].URL = devdocs.WebURL(results[i].Slug, results[i].Path)
+		results[i].Kind = NormalizeType(results[i].Type, results[i].Name)
 	}
 
-	// Collect all entries from all indices with their source slug
-	type indexedEntry struct {
-		entry devdocs.Entry
-		slug  string
+	return results, warning, nil
+}
+
+// searchSlugs returns the slugs to search, in index order, restricted to docSlugs if given.
+func (e *Engine) searchSlugs(docSlugs []string) []string {
+	if e.loader != nil {
+		if len(docSlugs) == 0 {
+			return e.slugs
+		}
+		var slugs []string
+		for _, slug := range docSlugs {
+			if slices.Contains(e.slugs, slug) {
+				slugs = append(slugs, slug)
+			}
+		}
+		return slugs
 	}
-	var allEntries []indexedEntry
-	for _, idx := range indicesToSearch {
-		// Direct O(1) lookup using reverse map
-		slug := e.slugsByIndex[idx]
-		for _, entry := range idx.Entries {
-			allEntries = append(allEntries, indexedEntry{entry: entry, slug: slug})
+
+	var slugs []string
+	if len(docSlugs) == 0 {
+		for _, idx := range e.indices {
+			// Direct O(1) lookup using reverse map
+			slugs = append(slugs, e.slugsByIndex[idx])
+		}
+		return slugs
+	}
+	for _, slug := range docSlugs {
+		if _, ok := e.indicesBySlug[slug]; ok {
+			slugs = append(slugs, slug)
 		}
 	}
+	return slugs
+}
 
-	if len(allEntries) == 0 {
-		return nil, "", fmt.Errorf("no results found for %q", query)
+// index returns the index for slug, loading it in lazy mode.
+func (e *Engine) index(slug string) (*devdocs.Index, error) {
+	if e.loader == nil {
+		return e.indicesBySlug[slug], nil
+	}
+	idx, err := e.loader(slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index for %s: %w", slug, err)
 	}
+	return idx, nil
+}
 
-	// Apply fuzzy matching to rank results
-	names := make([]string, len(allEntries))
-	for i, ie := range allEntries {
-		names[i] = ie.entry.Name
+// match fuzzy-matches query against the entries of one index, applying the
+// type filter. It also returns the number of entries considered.
+func (e *Engine) match(query, slug string, idx *devdocs.Index) ([]Result, int) {
+	entries := idx.Entries
+	if len(e.types) > 0 {
+		entries = make([]devdocs.Entry, 0, len(idx.Entries))
+		for _, entry := range idx.Entries {
+			if matchesType(entry.Type, entry.Name, e.types) {
+				entries = append(entries, entry)
+			}
+		}
 	}
 
-	matches := fuzzy.Find(query, names)
+	// Apply fuzzy matching to rank results
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
 
-	// Build results with scores
-	for _, match := range matches {
-		ie := allEntries[match.Index]
+	var results []Result
+	for _, match := range fuzzy.Find(query, names) {
 		results = append(results, Result{
-			Entry: ie.entry,
-			Slug:  ie.slug,
+			Entry: entries[match.Index],
+			Slug:  slug,
 			Score: float64(match.Score) / 100.0, // Normalize to 0-1
 		})
 	}
+	return results, len(entries)
+}
 
-	// Sort by score (descending) then by name
+// top sorts results by score (descending) then by name, and applies the limit.
+func (e *Engine) top(results []Result) []Result {
 	sort.Slice(results, func(i, j int) bool {
 		if results[i].Score != results[j].Score {
 			return results[i].Score > results[j].Score
@@ -112,10 +210,8 @@ func (e *Engine) Search(query string, docSlugs []string) ([]Result, string, erro
 		return results[i].Entry.Name < results[j].Entry.Name
 	})
 
-	// Limit results
 	if len(results) > e.limit {
 		results = results[:e.limit]
 	}
-
-	return results, warning, nil
+	return results
 }
@@ -1,6 +1,8 @@
 package search
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/icampana/dsearch/internal/devdocs"
@@ -127,3 +129,75 @@ func TestEngine_Limit(t *testing.T) {
 		t.Errorf("Expected 5 results, got %d", len(results))
 	}
 }
+
+func TestEngine_Lazy(t *testing.T) {
+	t.Parallel()
+
+	engine := loadGoldenEngine(t)
+	slugs := make([]string, 0, len(engine.indicesBySlug))
+	for _, idx := range engine.indices {
+		slugs = append(slugs, engine.slugsByIndex[idx])
+	}
+
+	loads := 0
+	lazy := NewLazy(slugs, func(slug string) (*devdocs.Index, error) {
+		loads++
+		idx, ok := engine.indicesBySlug[slug]
+		if !ok {
+			return nil, fmt.Errorf("unknown doc %s", slug)
+		}
+		return idx, nil
+	}, engine.limit)
+
+	for _, query := range []string{"useState", "map", "http.Client", "json.loads"} {
+		want, _, err := engine.Search(query, nil)
+		if err != nil {
+			t.Fatalf("Engine.Search(%q) error = %v", query, err)
+		}
+		got, _, err := lazy.Search(query, nil)
+		if err != nil {
+			t.Fatalf("lazy Engine.Search(%q) error = %v", query, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("lazy Engine.Search(%q) got %d results, want %d", query, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Score != want[i].Score || got[i].Name != want[i].Name {
+				t.Errorf("lazy Engine.Search(%q)[%d] = %s (%.2f), want %s (%.2f)",
+					query, i, got[i].Name, got[i].Score, want[i].Name, want[i].Score)
+			}
+		}
+	}
+	if want := 4 * len(slugs); loads != want {
+		t.Errorf("loader called %d times, want %d", loads, want)
+	}
+
+	if _, _, err := lazy.Search("map", []string{"react"}); err != nil {
+		t.Errorf("lazy Engine.Search() with doc filter error = %v", err)
+	}
+
+	broken := NewLazy([]string{"missing"}, func(slug string) (*devdocs.Index, error) {
+		return nil, fmt.Errorf("unknown doc %s", slug)
+	}, 10)
+	if _, _, err := broken.Search("map", nil); err == nil {
+		t.Error("lazy Engine.Search() expected error for a failing loader")
+	}
+
+	// A doc whose index can't be read is skipped with a warning
+	partial := NewLazy(slugs, func(slug string) (*devdocs.Index, error) {
+		if slug == "go" {
+			return nil, fmt.Errorf("corrupt index")
+		}
+		return engine.indicesBySlug[slug], nil
+	}, engine.limit)
+	results, warning, err := partial.Search("useState", nil)
+	if err != nil {
+		t.Fatalf("lazy Engine.Search() with one failing doc error = %v", err)
+	}
+	if len(results) == 0 || results[0].Name != "useState" {
+		t.Errorf("lazy Engine.Search() with one failing doc = %+v, want useState first", results)
+	}
+	if !strings.Contains(warning, "go") || !strings.Contains(warning, "corrupt index") {
+		t.Errorf("lazy Engine.Search() warning = %q, want the failing doc", warning)
+	}
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+// goldenCase is a query whose expected entry must rank in the top 3.
+type goldenCase struct {
+	Query string   `yaml:"query"`
+	Docs  []string `yaml:"docs"`
+	Want  string   `yaml:"want"` // <slug>:<name>
+}
+
+// loadGoldenEngine builds an engine over every fixture index in testdata.
+func loadGoldenEngine(t *testing.T) *Engine {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var indices []*devdocs.Index
+	indicesBySlug := make(map[string]*devdocs.Index)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var index devdocs.Index
+		if err := json.Unmarshal(data, &index); err != nil {
+			t.Fatalf("parsing %s: %v", file, err)
+		}
+		slug := strings.TrimSuffix(filepath.Base(file), ".json")
+		indices = append(indices, &index)
+		indicesBySlug[slug] = &index
+	}
+
+	return New(indices, indicesBySlug, 3)
+}
+
+func TestGoldenRanking(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "golden.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []goldenCase
+	if err := yaml.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("parsing golden.yaml: %v", err)
+	}
+
+	engine := loadGoldenEngine(t)
+
+	for _, tc := range cases {
+		t.Run(tc.Query, func(t *testing.T) {
+			t.Parallel()
+
+			results, _, err := engine.Search(tc.Query, tc.Docs)
+			if err != nil {
+				t.Fatalf("Engine.Search(%q) error = %v", tc.Query, err)
+			}
+
+			got := make([]string, len(results))
+			for i, r := range results {
+				got[i] = r.Slug + ":" + r.Name
+				if got[i] == tc.Want {
+					return
+				}
+			}
+			t.Errorf("Engine.Search(%q) top 3 = %v, want %s", tc.Query, got, tc.Want)
+		})
+	}
+}
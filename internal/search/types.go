@@ -0,0 +1,102 @@
+// Package search provides search functionality across DevDocs documentation.
+package search
+
+import "strings"
+
+// canonicalTypes folds source-specific entry types into a canonical taxonomy
+// based on the Kapeli/Dash entry types. Keys are lowercase.
+var canonicalTypes = map[string]string{
+	"annotation":  "Annotation",
+	"attribute":   "Attribute",
+	"builtin":     "Builtin",
+	"callback":    "Callback",
+	"class":       "Class",
+	"command":     "Command",
+	"component":   "Component",
+	"constant":    "Constant",
+	"constructor": "Constructor",
+	"directive":   "Directive",
+	"element":     "Element",
+	"enum":        "Enum",
+	"event":       "Event",
+	"exception":   "Exception",
+	"field":       "Field",
+	"filter":      "Filter",
+	"function":    "Function",
+	"guide":       "Guide",
+	"hook":        "Hook",
+	"interface":   "Interface",
+	"keyword":     "Keyword",
+	"macro":       "Macro",
+	"method":      "Method",
+	"module":      "Module",
+	"namespace":   "Namespace",
+	"object":      "Object",
+	"operator":    "Operator",
+	"option":      "Option",
+	"package":     "Package",
+	"property":    "Property",
+	"protocol":    "Protocol",
+	"statement":   "Statement",
+	"struct":      "Struct",
+	"tag":         "Tag",
+	"trait":       "Trait",
+	"type":        "Type",
+	"variable":    "Variable",
+
+	// Plural and descriptive DevDocs category names
+	"built-in functions":  "Function",
+	"global functions":    "Function",
+	"functions":           "Function",
+	"api":                 "Function",
+	"apis":                "Function",
+	"classes":             "Class",
+	"errors":              "Exception",
+	"exceptions":          "Exception",
+	"built-in exceptions": "Exception",
+	"hooks":               "Hook",
+	"html elements":       "Element",
+	"elements":            "Element",
+	"attributes":          "Attribute",
+	"properties":          "Property",
+	"types":               "Type",
+	"built-in types":      "Type",
+	"guides":              "Guide",
+	"tutorial":            "Guide",
+	"tutorials":           "Guide",
+}
+
+// NormalizeType maps an entry's source-specific type to a canonical type
+// (e.g. "hooks" -> "Hook", "Built-in Functions" -> "Function").
+// When the type is only a category (like a package name), the entry name is
+// used as a hint. Returns "" when no canonical type applies.
+func NormalizeType(typ, name string) string {
+	key := strings.ToLower(strings.TrimSpace(typ))
+	if canonical, ok := canonicalTypes[key]; ok {
+		return canonical
+	}
+	// Simple plurals of canonical types ("Methods", "Directives")
+	if canonical, ok := canonicalTypes[strings.TrimSuffix(key, "s")]; ok {
+		return canonical
+	}
+
+	switch {
+	case strings.Contains(name, ".prototype."):
+		return "Method"
+	case strings.HasSuffix(name, "()"):
+		return "Function"
+	}
+	return ""
+}
+
+// matchesType reports whether an entry matches any of the requested types,
+// comparing against both the canonical and the original type.
+func matchesType(entryType, name string, types []string) bool {
+	canonical := NormalizeType(entryType, name)
+	for _, t := range types {
+		if strings.EqualFold(t, entryType) || (canonical != "" && strings.EqualFold(NormalizeType(t, ""), canonical)) {
+			return true
+		}
+	}
+	return false
+}
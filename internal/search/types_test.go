@@ -0,0 +1,80 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/icampana/dsearch/internal/devdocs"
+)
+
+func TestNormalizeType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		typ  string
+		name string
+		want string
+	}{
+		{typ: "Hooks", name: "useState", want: "Hook"},
+		{typ: "hooks", name: "useEffect", want: "Hook"},
+		{typ: "Function", name: "NSLog", want: "Function"},
+		{typ: "Built-in Functions", name: "len()", want: "Function"},
+		{typ: "Classes", name: "Foo", want: "Class"},
+		{typ: "Methods", name: "Foo.bar", want: "Method"},
+		{typ: "Array", name: "Array.prototype.map()", want: "Method"},
+		{typ: "json", name: "json.loads()", want: "Function"},
+		{typ: "net/http", name: "http.Client", want: ""},
+		{typ: "localize", name: "$localize", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeType(tt.typ, tt.name); got != tt.want {
+			t.Errorf("NormalizeType(%q, %q) = %q, want %q", tt.typ, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEngine_TypeFilter(t *testing.T) {
+	t.Parallel()
+
+	react := &devdocs.Index{Entries: []devdocs.Entry{
+		{Name: "useState", Path: "hooks/usestate", Type: "Hooks"},
+		{Name: "useId", Path: "hooks/useid", Type: "Hooks"},
+		{Name: "Suspense", Path: "components/suspense", Type: "Components"},
+	}}
+	dash := &devdocs.Index{Entries: []devdocs.Entry{
+		{Name: "useSomething", Path: "hook.html", Type: "Hook"},
+		{Name: "userFunc", Path: "func.html", Type: "Function"},
+	}}
+	indicesBySlug := map[string]*devdocs.Index{"react": react, "custom": dash}
+
+	tests := []struct {
+		name      string
+		types     []string
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "No filter", types: nil, wantCount: 5},
+		{name: "Canonical type across sources", types: []string{"hook"}, wantCount: 3},
+		{name: "Original type", types: []string{"Hooks"}, wantCount: 3},
+		{name: "Multiple types", types: []string{"Hook", "Function"}, wantCount: 4},
+		{name: "Canonical type from plural", types: []string{"component"}, wantCount: 1},
+		{name: "No matching type", types: []string{"Class"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			engine := New([]*devdocs.Index{react, dash}, indicesBySlug, 10)
+			engine.SetTypeFilter(tt.types)
+
+			results, _, err := engine.Search("use", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Engine.Search() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(results) != tt.wantCount {
+				t.Errorf("Engine.Search() got %d results, want %d", len(results), tt.wantCount)
+			}
+		})
+	}
+}